@@ -1,11 +1,13 @@
 module github.com/mainak90/terraform-provider-mysql
 
-go 1.14
+go 1.15
 
 require (
-	github.com/go-sql-driver/mysql v1.5.0
-	github.com/hashicorp/go-version v1.2.1 // indirect
-	github.com/hashicorp/terraform v0.14.10 // indirect
+	github.com/GoogleCloudPlatform/cloudsql-proxy v1.21.0
+	github.com/aws/aws-sdk-go v1.37.0
+	github.com/go-sql-driver/mysql v1.6.0
+	github.com/hashicorp/go-version v1.2.1
+	github.com/hashicorp/hcl/v2 v2.9.1 // indirect
 	github.com/hashicorp/terraform-plugin-sdk v1.16.1
-	golang.org/x/net v0.0.0-20201110031124-69a78807bb2b
+	golang.org/x/net v0.0.0-20210405180319-a5a99cb37ef4
 )
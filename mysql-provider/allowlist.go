@@ -0,0 +1,43 @@
+package mysql_provider
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// validateSchemaPattern accepts the glob patterns of managed_schema_allowlist,
+// e.g. "app_*".
+func validateSchemaPattern(v interface{}, k string) (ws []string, errs []error) {
+	if _, err := path.Match(v.(string), ""); err != nil {
+		errs = append(errs, fmt.Errorf("%s: %q is not a valid pattern: %s", k, v, err))
+	}
+	return
+}
+
+// checkManagedSchema returns an error unless the database name matches one of
+// the provider's managed_schema_allowlist patterns. Without an allow-list every
+// database may be managed.
+func (c *MySQLConfiguration) checkManagedSchema(name string) error {
+	if len(c.ManagedSchemaAllowlist) == 0 {
+		return nil
+	}
+	for _, pattern := range c.ManagedSchemaAllowlist {
+		if ok, _ := path.Match(pattern, name); ok {
+			return nil
+		}
+	}
+	return fmt.Errorf("Database %s is not in the provider's managed_schema_allowlist (%s)", name, strings.Join(c.ManagedSchemaAllowlist, ", "))
+}
+
+// validateManagedObjectSchema rejects plans for objects in a database outside
+// the provider's managed_schema_allowlist, for the resources naming theirs in
+// a database attribute.
+func validateManagedObjectSchema(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("database") {
+		return nil
+	}
+	return meta.(*MySQLConfiguration).checkManagedSchema(d.Get("database").(string))
+}
@@ -0,0 +1,70 @@
+package mysql_provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateSchemaPattern(t *testing.T) {
+	for _, pattern := range []string{"app", "app_*", "tenant_??", "[ab]*"} {
+		if _, errs := validateSchemaPattern(pattern, "managed_schema_allowlist"); len(errs) != 0 {
+			t.Errorf("validateSchemaPattern(%q) returned %v", pattern, errs)
+		}
+	}
+	_, errs := validateSchemaPattern("app_[", "managed_schema_allowlist.0")
+	if len(errs) == 0 {
+		t.Fatal("validateSchemaPattern(\"app_[\") returned no errors")
+	}
+	if expected := `managed_schema_allowlist.0: "app_[" is not a valid pattern: `; !strings.HasPrefix(errs[0].Error(), expected) {
+		t.Errorf("validateSchemaPattern(\"app_[\") returned %q, want it to start with %q", errs[0], expected)
+	}
+}
+
+func TestCheckManagedSchema(t *testing.T) {
+	unrestricted := &MySQLConfiguration{}
+	if err := unrestricted.checkManagedSchema("anything"); err != nil {
+		t.Errorf("checkManagedSchema without an allow-list returned %s", err)
+	}
+
+	conf := &MySQLConfiguration{ManagedSchemaAllowlist: []string{"app_*", "reporting"}}
+	cases := map[string]bool{
+		"app_users":    true,
+		"reporting":    true,
+		"reporting_v2": false,
+		"mysql":        false,
+	}
+	for name, allowed := range cases {
+		if err := conf.checkManagedSchema(name); (err == nil) != allowed {
+			t.Errorf("checkManagedSchema(%q) returned %v, want allowed %t", name, err, allowed)
+		}
+	}
+}
+
+func TestValidateManagedSchema(t *testing.T) {
+	server := newFakeServer(t, nil)
+	conf, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint":                 server.addr(),
+		"managed_schema_allowlist": []interface{}{"app_*"},
+	})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+
+	if _, err := testResourceDiff(t, ResourceDB(), nil, map[string]interface{}{"name": "app_users"}, conf); err != nil {
+		t.Errorf("plan of an allowed database returned %s", err)
+	}
+	_, err = testResourceDiff(t, ResourceDB(), nil, map[string]interface{}{"name": "billing"}, conf)
+	if err == nil || !strings.Contains(err.Error(), "not in the provider's managed_schema_allowlist") {
+		t.Errorf("plan of a database outside the allow-list returned %v", err)
+	}
+
+	d := ResourceDB().Data(nil)
+	d.SetId("billing")
+	err = DeleteDb(d, conf)
+	if err == nil || !strings.Contains(err.Error(), "managed_schema_allowlist") {
+		t.Errorf("DeleteDb of a database outside the allow-list returned %v", err)
+	}
+	if fakeQueriesContain(server.receivedQueries(), "DROP DATABASE") {
+		t.Errorf("DeleteDb outside the allow-list ran %q", server.receivedQueries())
+	}
+}
@@ -0,0 +1,63 @@
+package mysql_provider
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"database/sql"
+	"encoding/pem"
+	"fmt"
+	"log"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const cachingSHA2Plugin = "caching_sha2_password"
+
+// warnAuthPluginMismatch compares the server side plugin of the connected
+// account with authentication_plugin. Connecting with native to a
+// caching_sha2_password account works while the server caches the password
+// hash, but the full authentication needed after a restart or a FLUSH
+// PRIVILEGES then runs without TLS and without a trusted server public key.
+// Lookup errors only disable the check.
+func warnAuthPluginMismatch(db *sql.DB, authPlugin string) {
+	stmtSQL := "SELECT `plugin` FROM `mysql`.`user` WHERE CONCAT(`User`, '@', `Host`) = CURRENT_USER()"
+	logQuery(stmtSQL)
+
+	var plugin string
+	if err := db.QueryRow(stmtSQL).Scan(&plugin); err != nil {
+		log.Printf("[WARN] Could not check the authentication plugin of the configured user: %s", err)
+		return
+	}
+
+	if plugin == cachingSHA2Plugin && authPlugin == nativePasswords {
+		log.Printf("[WARN] The configured user authenticates with %s but authentication_plugin is %s, set authentication_plugin to %s and enable tls or set server_public_key", cachingSHA2Plugin, nativePasswords, cachingSHA2Passwords)
+	}
+}
+
+// registerServerPublicKey registers the server_public_key with the driver and
+// returns the name it was registered under.
+func registerServerPublicKey(value string) (string, error) {
+	data, err := readPEM(value)
+	if err != nil {
+		return "", fmt.Errorf("Could not read server_public_key: %s", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return "", fmt.Errorf("server_public_key does not contain a PEM block")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return "", fmt.Errorf("Could not parse server_public_key: %s", err)
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return "", fmt.Errorf("server_public_key must be an RSA public key")
+	}
+
+	sum := sha256.Sum256(block.Bytes)
+	name := fmt.Sprintf("server-%x", sum[:8])
+	mysql.RegisterServerPubKey(name, rsaKey)
+
+	return name, nil
+}
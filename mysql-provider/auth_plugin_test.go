@@ -0,0 +1,146 @@
+package mysql_provider
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"strings"
+	"testing"
+)
+
+// testPublicKeyPEM returns the public key of key, PEM encoded.
+func testPublicKeyPEM(t *testing.T, key crypto.PublicKey) string {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func TestProviderConfigureAuthPluginMismatch(t *testing.T) {
+	cases := []struct {
+		result     fakeResult
+		authPlugin string
+		expected   string
+	}{
+		{fakeRow("plugin", cachingSHA2Plugin), nativePasswords, "but authentication_plugin is native"},
+		{fakeRow("plugin", "mysql_native_password"), nativePasswords, ""},
+		// Accounts without SELECT on mysql.user can't tell, which mustn't
+		// fail the configure.
+		{fakeError(1142, "SELECT command denied to user 'tf'@'%' for table 'user'"), nativePasswords, "Could not check the authentication plugin"},
+	}
+	for _, c := range cases {
+		result := c.result
+		server := newFakeServer(t, func(query string) fakeResult {
+			if strings.Contains(query, "FROM `mysql`.`user`") {
+				return result
+			}
+			return fakeDefaultResult(query)
+		})
+		output := testCaptureLog(t)
+
+		_, err := testProviderConfigure(t, map[string]interface{}{
+			"endpoint":              server.addr(),
+			"authentication_plugin": c.authPlugin,
+			"check_auth_plugin":     true,
+		})
+		if err != nil {
+			t.Fatalf("providerConfigure returned %s", err)
+		}
+		if !fakeQueriesContain(server.receivedQueries(), "FROM `mysql`.`user`") {
+			t.Errorf("providerConfigure with check_auth_plugin didn't look up the plugin, got %q", server.receivedQueries())
+		}
+		logged := output.String()
+		if c.expected == "" && strings.Contains(logged, "authentication_plugin is") {
+			t.Errorf("providerConfigure with %s warned about a mismatch: %q", c.authPlugin, logged)
+		}
+		if c.expected != "" && !strings.Contains(logged, c.expected) {
+			t.Errorf("providerConfigure with %s logged %q, want %q", c.authPlugin, logged, c.expected)
+		}
+	}
+}
+
+func TestRegisterServerPublicKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name, err := registerServerPublicKey(testPublicKeyPEM(t, &rsaKey.PublicKey))
+	if err != nil {
+		t.Fatalf("registerServerPublicKey returned %s", err)
+	}
+	if !strings.HasPrefix(name, "server-") {
+		t.Errorf("registerServerPublicKey registered the key as %q", name)
+	}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]string{
+		testPublicKeyPEM(t, &ecKey.PublicKey):                             "must be an RSA public key",
+		"-----BEGIN PUBLIC KEY-----\nnot a key\n-----END PUBLIC KEY-----": "PEM block",
+	}
+	for value, expected := range cases {
+		if _, err := registerServerPublicKey(value); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("registerServerPublicKey returned %v, want it to mention %q", err, expected)
+		}
+	}
+}
+
+func TestProviderConfigureCachingSHA2(t *testing.T) {
+	server := newFakeServer(t, nil)
+
+	_, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint":              server.addr(),
+		"authentication_plugin": cachingSHA2Passwords,
+	})
+	if err == nil || !strings.Contains(err.Error(), "needs tls or server_public_key") {
+		t.Errorf("providerConfigure with caching_sha2 over plain TCP returned %v", err)
+	}
+	if attempts := server.loginAttempts(); attempts != 0 {
+		t.Errorf("providerConfigure with caching_sha2 over plain TCP logged in %d times", attempts)
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint":              server.addr(),
+		"authentication_plugin": cachingSHA2Passwords,
+		"server_public_key":     testPublicKeyPEM(t, &key.PublicKey),
+	})
+	if err != nil {
+		t.Fatalf("providerConfigure with server_public_key returned %s", err)
+	}
+	if conf.Config.ServerPubKey == "" {
+		t.Error("providerConfigure didn't pass server_public_key to the driver")
+	}
+	if server.loginAttempts() == 0 {
+		t.Error("providerConfigure with server_public_key didn't connect")
+	}
+
+	// The server trusts unix sockets, they need neither.
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(dir) })
+	newFakeServerListening(t, "unix", "mysql.sock", nil)
+	if _, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint":              "mysql.sock",
+		"protocol":              "unix",
+		"authentication_plugin": cachingSHA2Passwords,
+	}); err != nil {
+		t.Errorf("providerConfigure with caching_sha2 over a unix socket returned %s", err)
+	}
+}
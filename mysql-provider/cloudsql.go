@@ -0,0 +1,29 @@
+package mysql_provider
+
+import (
+	"context"
+	"net"
+	"regexp"
+
+	cloudsqlproxy "github.com/GoogleCloudPlatform/cloudsql-proxy/proxy/proxy"
+	"github.com/go-sql-driver/mysql"
+)
+
+const cloudSQLProtocol = "cloudsql"
+
+// cloudSQLInstanceRegexp matches instance connection names such as
+// "my-project:us-central1:my-instance", optionally domain scoped.
+var cloudSQLInstanceRegexp = regexp.MustCompile(`^([a-z0-9.-]+:)?[a-z][a-z0-9-]*:[a-z0-9-]+:[a-z][a-z0-9-]*$`)
+
+// cloudSQLDial connects to an instance, tests replace it to stay away from
+// Google Cloud.
+var cloudSQLDial = cloudsqlproxy.DialContext
+
+// registerCloudSQLDialer makes the "cloudsql" network available to the driver.
+// Connections are tunnelled through the Cloud SQL proxy client, which picks
+// up the application default credentials on first use.
+func registerCloudSQLDialer() {
+	mysql.RegisterDialContext(cloudSQLProtocol, func(ctx context.Context, instance string) (net.Conn, error) {
+		return cloudSQLDial(ctx, instance)
+	})
+}
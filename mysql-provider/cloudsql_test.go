@@ -0,0 +1,34 @@
+package mysql_provider
+
+import (
+	"context"
+	"net"
+	"testing"
+)
+
+func TestProviderConfigureCloudSQL(t *testing.T) {
+	server := newFakeServer(t, nil)
+
+	var dialed []string
+	previous := cloudSQLDial
+	cloudSQLDial = func(ctx context.Context, instance string) (net.Conn, error) {
+		dialed = append(dialed, instance)
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, "tcp", server.addr())
+	}
+	t.Cleanup(func() { cloudSQLDial = previous })
+
+	conf, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint": "my-project:us-central1:my-instance",
+		"protocol": cloudSQLProtocol,
+	})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+	if conf.Config.Net != cloudSQLProtocol || conf.Config.Addr != "my-project:us-central1:my-instance" {
+		t.Errorf("providerConfigure connects to %s over %s, want the instance over %s", conf.Config.Addr, conf.Config.Net, cloudSQLProtocol)
+	}
+	if len(dialed) == 0 || dialed[0] != "my-project:us-central1:my-instance" {
+		t.Errorf("the cloudsql dialer dialed %q, want the instance", dialed)
+	}
+}
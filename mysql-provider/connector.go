@@ -0,0 +1,47 @@
+package mysql_provider
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+)
+
+// initConnector runs the connection_init_sql statements on every connection
+// the pool opens, before handing it out.
+type initConnector struct {
+	driver.Connector
+	initSQL []string
+}
+
+func (c *initConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	execer, ok := conn.(driver.ExecerContext)
+	if !ok && len(c.initSQL) > 0 {
+		conn.Close()
+		return nil, fmt.Errorf("The connection can't run connection_init_sql")
+	}
+	for _, stmtSQL := range c.initSQL {
+		logStatement(stmtSQL)
+		if _, err := execer.ExecContext(ctx, stmtSQL, nil); err != nil {
+			conn.Close()
+			return nil, &initSQLError{stmtSQL: stmtSQL, err: err}
+		}
+	}
+
+	return conn, nil
+}
+
+// initSQLError is a failed connection_init_sql statement. Retrying won't make
+// it succeed, so connecting gives up on it right away.
+type initSQLError struct {
+	stmtSQL string
+	err     error
+}
+
+func (e *initSQLError) Error() string {
+	return fmt.Sprintf("Error running connection_init_sql %q: %s", redactSQL(e.stmtSQL), e.err)
+}
@@ -0,0 +1,55 @@
+package mysql_provider
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProviderConfigureConnectionInitSQL(t *testing.T) {
+	server := newFakeServer(t, func(query string) fakeResult {
+		if query == "SET @broken = broken()" {
+			return fakeError(1305, "FUNCTION broken does not exist")
+		}
+		return fakeDefaultResult(query)
+	})
+	initSQL := []interface{}{"SET time_zone = '+00:00'", "SET @app = 'terraform'"}
+
+	conf, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint":            server.addr(),
+		"connection_init_sql": initSQL,
+	})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+
+	// Every new connection runs the statements, in order, before anything
+	// but the driver's own queries.
+	server.dropConnections()
+	before := len(server.receivedQueries())
+	if _, err := conf.connection().Exec("DO 1"); err != nil {
+		t.Fatalf("Exec returned %s", err)
+	}
+	var queries []string
+	for _, query := range server.receivedQueries()[before:] {
+		if query != "SELECT @@max_allowed_packet" {
+			queries = append(queries, query)
+		}
+	}
+	if len(queries) != 3 || queries[0] != initSQL[0] || queries[1] != initSQL[1] {
+		t.Errorf("the new connection ran %q, want %q first", queries, initSQL)
+	}
+
+	start := time.Now()
+	_, err = testProviderConfigure(t, map[string]interface{}{
+		"endpoint":                  server.addr(),
+		"connection_init_sql":       []interface{}{"SET @broken = broken()"},
+		"connect_retry_timeout_sec": 30,
+	})
+	if err == nil || !strings.Contains(err.Error(), "Error running connection_init_sql") {
+		t.Errorf("providerConfigure with a failing statement returned %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("providerConfigure retried the failing statement for %s", elapsed)
+	}
+}
@@ -0,0 +1,58 @@
+package mysql_provider
+
+import (
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func DataSourceConnectionStats() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"max_open_connections": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"open_connections": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"in_use": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"idle": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"wait_count": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			// Total time spent waiting for a connection, in milliseconds.
+			"wait_duration_ms": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+		Read: ReadConnectionStats,
+	}
+}
+
+// ReadConnectionStats reports the pool statistics of the shared connection
+// at the time the data source is read.
+func ReadConnectionStats(d *schema.ResourceData, meta interface{}) error {
+	stats := meta.(*MySQLConfiguration).connection().Stats()
+
+	// Config.Addr follows failovers and is written under dbLock, the first
+	// configured endpoint identifies the provider for good.
+	d.SetId(meta.(*MySQLConfiguration).Endpoints[0])
+	d.Set("max_open_connections", stats.MaxOpenConnections)
+	d.Set("open_connections", stats.OpenConnections)
+	d.Set("in_use", stats.InUse)
+	d.Set("idle", stats.Idle)
+	d.Set("wait_count", int(stats.WaitCount))
+	d.Set("wait_duration_ms", int(stats.WaitDuration/time.Millisecond))
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package mysql_provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestReadConnectionStats(t *testing.T) {
+	server := newFakeServer(t, nil)
+	conf, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint":       server.addr(),
+		"max_open_conns": 4,
+	})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := conf.connection().Exec("DO 1"); err != nil {
+			t.Fatalf("Exec returned %s", err)
+		}
+	}
+
+	d := schema.TestResourceDataRaw(t, DataSourceConnectionStats().Schema, map[string]interface{}{})
+	if err := ReadConnectionStats(d, conf); err != nil {
+		t.Fatalf("ReadConnectionStats returned %s", err)
+	}
+	if d.Id() != server.addr() {
+		t.Errorf("ID = %q, want the endpoint %q", d.Id(), server.addr())
+	}
+	if got := d.Get("max_open_connections").(int); got != 4 {
+		t.Errorf("max_open_connections = %d, want 4", got)
+	}
+	if got := d.Get("open_connections").(int); got < 1 {
+		t.Errorf("open_connections = %d after running statements, want at least 1", got)
+	}
+	for _, key := range []string{"in_use", "idle", "wait_count", "wait_duration_ms"} {
+		if got := d.Get(key).(int); got < 0 {
+			t.Errorf("%s = %d, want it non-negative", key, got)
+		}
+	}
+}
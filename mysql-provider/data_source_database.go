@@ -0,0 +1,49 @@
+package mysql_provider
+
+import (
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func DataSourceDatabase() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"default_charset": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"default_collation": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+		Read: ReadDatabaseDataSource,
+	}
+}
+
+func ReadDatabaseDataSource(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	name := d.Get("name").(string)
+	options, err := readDatabaseOptions(ctx, meta.(*MySQLConfiguration), db, name)
+	if err != nil {
+		if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == unknownDatabaseErr {
+			return fmt.Errorf("Database %s does not exist", name)
+		}
+		return fmt.Errorf("Error reading database %s: %s", name, err)
+	}
+
+	d.SetId(name)
+	d.Set("default_charset", options.Charset)
+	d.Set("default_collation", options.Collation)
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package mysql_provider
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestAccDataSourceDatabase(t *testing.T) {
+	name := testAccDatabasePrefix + acctest.RandString(8)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccDatabaseCheckDestroy(name),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name              = %q
+  default_charset   = "latin1"
+  default_collation = "latin1_bin"
+}
+
+data "mysql_database" "test" {
+  name = mysql_database.test.name
+}
+`, name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mysql_database.test", "default_charset", "latin1"),
+					resource.TestCheckResourceAttr("data.mysql_database.test", "default_collation", "latin1_bin"),
+				),
+			},
+		},
+	})
+}
+
+func TestReadDatabaseDataSource(t *testing.T) {
+	server := newFakeServer(t, func(query string) fakeResult {
+		switch query {
+		case "SHOW CREATE DATABASE `app`":
+			return fakeResult{
+				columns: []fakeColumn{{"Database", fakeTypeVarString}, {"Create Database", fakeTypeVarString}},
+				rows:    [][]interface{}{{"app", "CREATE DATABASE `app` /*!40100 DEFAULT CHARACTER SET latin1 COLLATE latin1_bin */"}},
+			}
+		case "SHOW CREATE DATABASE `gone`":
+			return fakeError(unknownDatabaseErr, "Unknown database 'gone'")
+		}
+		return fakeDefaultResult(query)
+	})
+	conf, err := testProviderConfigure(t, map[string]interface{}{"endpoint": server.addr()})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+
+	d := schema.TestResourceDataRaw(t, DataSourceDatabase().Schema, map[string]interface{}{"name": "app"})
+	if err := ReadDatabaseDataSource(d, conf); err != nil {
+		t.Fatalf("ReadDatabaseDataSource returned %s", err)
+	}
+	if got := d.Get("default_charset").(string); got != "latin1" {
+		t.Errorf("default_charset = %q, want latin1", got)
+	}
+	if got := d.Get("default_collation").(string); got != "latin1_bin" {
+		t.Errorf("default_collation = %q, want latin1_bin", got)
+	}
+
+	d = schema.TestResourceDataRaw(t, DataSourceDatabase().Schema, map[string]interface{}{"name": "gone"})
+	err = ReadDatabaseDataSource(d, conf)
+	if err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("ReadDatabaseDataSource of an unknown database returned %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package mysql_provider
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// systemDatabases are the schemas the server creates and maintains itself.
+var systemDatabases = map[string]bool{
+	"information_schema": true,
+	"mysql":              true,
+	"performance_schema": true,
+	"sys":                true,
+}
+
+func DataSourceDatabases() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"pattern": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"exclude_system_databases": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"databases": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+		Read: ReadDatabases,
+	}
+}
+
+func ReadDatabases(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	pattern := d.Get("pattern").(string)
+	excludeSystem := d.Get("exclude_system_databases").(bool)
+
+	stmtSQL := "SHOW DATABASES"
+	args := []interface{}{}
+	if pattern != "" {
+		stmtSQL += " LIKE ?"
+		args = append(args, pattern)
+	}
+
+	logQuery(stmtSQL)
+	rows, err := db.QueryContext(ctx, stmtSQL, args...)
+	if err != nil {
+		return fmt.Errorf("Error listing databases: %s", err)
+	}
+	defer rows.Close()
+
+	databases := []string{}
+	for rows.Next() {
+		var database string
+		if err := rows.Scan(&database); err != nil {
+			return fmt.Errorf("Error listing databases: %s", err)
+		}
+		if excludeSystem && systemDatabases[database] {
+			continue
+		}
+		databases = append(databases, database)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("Error listing databases: %s", err)
+	}
+
+	d.SetId(fmt.Sprintf("%s:%t", pattern, excludeSystem))
+	d.Set("databases", databases)
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package mysql_provider
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccDataSourceDatabases(t *testing.T) {
+	name := testAccDatabasePrefix + acctest.RandString(8)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccDatabaseCheckDestroy(name),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name = %q
+}
+
+data "mysql_databases" "all" {
+  exclude_system_databases = true
+  depends_on               = [mysql_database.test]
+}
+
+data "mysql_databases" "matching" {
+  pattern    = mysql_database.test.name
+  depends_on = [mysql_database.test]
+}
+`, name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mysql_databases.matching", "databases.#", "1"),
+					resource.TestCheckResourceAttr("data.mysql_databases.matching", "databases.0", name),
+					func(s *terraform.State) error {
+						attributes := s.RootModule().Resources["data.mysql_databases.all"].Primary.Attributes
+						found := false
+						for k, v := range attributes {
+							if !strings.HasPrefix(k, "databases.") || k == "databases.#" {
+								continue
+							}
+							if systemDatabases[v] {
+								return fmt.Errorf("exclude_system_databases listed %s", v)
+							}
+							found = found || v == name
+						}
+						if !found {
+							return fmt.Errorf("databases doesn't list %s", name)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestReadDatabases(t *testing.T) {
+	server := newFakeServer(t, func(query string) fakeResult {
+		if strings.HasPrefix(query, "SHOW DATABASES") {
+			return fakeResult{
+				columns: []fakeColumn{{"Database", fakeTypeVarString}},
+				rows:    [][]interface{}{{"app"}, {"information_schema"}, {"mysql"}, {"performance_schema"}, {"reports"}, {"sys"}},
+			}
+		}
+		return fakeDefaultResult(query)
+	})
+	conf, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint":           server.addr(),
+		"interpolate_params": true,
+	})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+
+	cases := []struct {
+		raw      map[string]interface{}
+		expected []string
+	}{
+		{map[string]interface{}{}, []string{"app", "information_schema", "mysql", "performance_schema", "reports", "sys"}},
+		{map[string]interface{}{"exclude_system_databases": true}, []string{"app", "reports"}},
+	}
+	for _, c := range cases {
+		d := schema.TestResourceDataRaw(t, DataSourceDatabases().Schema, c.raw)
+		if err := ReadDatabases(d, conf); err != nil {
+			t.Fatalf("ReadDatabases(%v) returned %s", c.raw, err)
+		}
+		var databases []string
+		for _, database := range d.Get("databases").([]interface{}) {
+			databases = append(databases, database.(string))
+		}
+		if !reflect.DeepEqual(databases, c.expected) {
+			t.Errorf("ReadDatabases(%v) = %q, want %q", c.raw, databases, c.expected)
+		}
+	}
+
+	d := schema.TestResourceDataRaw(t, DataSourceDatabases().Schema, map[string]interface{}{"pattern": "app%"})
+	if err := ReadDatabases(d, conf); err != nil {
+		t.Fatalf("ReadDatabases returned %s", err)
+	}
+	if !fakeQueriesContain(server.receivedQueries(), "SHOW DATABASES LIKE 'app%'") {
+		t.Errorf("ReadDatabases with a pattern ran %q, want SHOW DATABASES LIKE 'app%%'", server.receivedQueries())
+	}
+}
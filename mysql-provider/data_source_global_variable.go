@@ -0,0 +1,41 @@
+package mysql_provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func DataSourceGlobalVariable() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringMatch(variableNameRegexp, "The variable name may only contain letters, digits, underscores and dots."),
+			},
+			// Empty for variables that are NULL, e.g. an unset init_file.
+			"value": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+		Read: ReadGlobalVariableDataSource,
+	}
+}
+
+func ReadGlobalVariableDataSource(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	name := d.Get("name").(string)
+	value, err := readGlobalVariable(ctx, db, name)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(name)
+	d.Set("value", value)
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package mysql_provider
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestAccDataSourceGlobalVariable(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "mysql_global_variable" "test" {
+  name = "max_connections"
+}
+`,
+				Check: resource.TestMatchResourceAttr("data.mysql_global_variable.test", "value", regexp.MustCompile(`^[0-9]+$`)),
+			},
+		},
+	})
+}
+
+func TestReadGlobalVariableDataSource(t *testing.T) {
+	server := newFakeServer(t, func(query string) fakeResult {
+		switch query {
+		case "SELECT @@GLOBAL.max_connections":
+			return fakeRow("@@GLOBAL.max_connections", "151")
+		case "SELECT @@GLOBAL.init_file":
+			return fakeRow("@@GLOBAL.init_file", nil)
+		case "SELECT @@GLOBAL.no_such_variable":
+			return fakeError(unknownSystemVariableErr, "Unknown system variable 'no_such_variable'")
+		}
+		return fakeDefaultResult(query)
+	})
+	conf, err := testProviderConfigure(t, map[string]interface{}{"endpoint": server.addr()})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+
+	for name, expected := range map[string]string{"max_connections": "151", "init_file": ""} {
+		d := schema.TestResourceDataRaw(t, DataSourceGlobalVariable().Schema, map[string]interface{}{"name": name})
+		if err := ReadGlobalVariableDataSource(d, conf); err != nil {
+			t.Fatalf("ReadGlobalVariableDataSource(%s) returned %s", name, err)
+		}
+		if value := d.Get("value").(string); value != expected {
+			t.Errorf("ReadGlobalVariableDataSource(%s) read %q, want %q", name, value, expected)
+		}
+		if d.Id() != name {
+			t.Errorf("ReadGlobalVariableDataSource(%s) set the ID %q", name, d.Id())
+		}
+	}
+
+	d := schema.TestResourceDataRaw(t, DataSourceGlobalVariable().Schema, map[string]interface{}{"name": "no_such_variable"})
+	err = ReadGlobalVariableDataSource(d, conf)
+	if err == nil || !strings.Contains(err.Error(), "Unknown global variable no_such_variable") {
+		t.Errorf("ReadGlobalVariableDataSource of an unknown variable returned %v", err)
+	}
+
+	// The name is validated again before it ends up in the statement.
+	d = schema.TestResourceDataRaw(t, DataSourceGlobalVariable().Schema, map[string]interface{}{"name": "version; DROP DATABASE app"})
+	err = ReadGlobalVariableDataSource(d, conf)
+	if err == nil || !strings.Contains(err.Error(), "Invalid global variable name") {
+		t.Errorf("ReadGlobalVariableDataSource of an invalid name returned %v", err)
+	}
+	if fakeQueriesContain(server.receivedQueries(), "DROP DATABASE") {
+		t.Errorf("ReadGlobalVariableDataSource ran %q", server.receivedQueries())
+	}
+}
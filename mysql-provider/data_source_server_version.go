@@ -0,0 +1,70 @@
+package mysql_provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func DataSourceServerVersion() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"version": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"version_string": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"is_mariadb": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			// is_rds is also set for Aurora.
+			"is_rds": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"is_aurora": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+		},
+		Read: ReadServerVersion,
+	}
+}
+
+func ReadServerVersion(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+
+	currentVersion, err := meta.(*MySQLConfiguration).serverVersion()
+	if err != nil {
+		return fmt.Errorf("Error reading server version: %s", err)
+	}
+
+	versionString, err := meta.(*MySQLConfiguration).serverVersionString()
+	if err != nil {
+		return fmt.Errorf("Error reading server version: %s", err)
+	}
+
+	rds, err := isRDS(db)
+	if err != nil {
+		return fmt.Errorf("Error detecting RDS: %s", err)
+	}
+
+	aurora, err := isAurora(db)
+	if err != nil {
+		return fmt.Errorf("Error detecting Aurora: %s", err)
+	}
+
+	d.SetId(versionString)
+	d.Set("version", currentVersion.String())
+	d.Set("version_string", versionString)
+	d.Set("is_mariadb", strings.Contains(versionString, "MariaDB"))
+	d.Set("is_rds", rds || aurora)
+	d.Set("is_aurora", aurora)
+
+	return nil
+}
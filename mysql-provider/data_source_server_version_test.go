@@ -0,0 +1,120 @@
+package mysql_provider
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccDataSourceServerVersion(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `data "mysql_server_version" "test" {}`,
+				Check: func(s *terraform.State) error {
+					attributes := s.RootModule().Resources["data.mysql_server_version.test"].Primary.Attributes
+					if _, err := version.NewVersion(attributes["version"]); err != nil {
+						return fmt.Errorf("version %q is not a semantic version: %s", attributes["version"], err)
+					}
+					if current, err := parseServerVersion(attributes["version_string"]); err != nil || current.String() != attributes["version"] {
+						return fmt.Errorf("version %q doesn't match the banner %q", attributes["version"], attributes["version_string"])
+					}
+					return resource.TestCheckResourceAttr("data.mysql_server_version.test", "is_mariadb", fmt.Sprint(strings.Contains(attributes["version_string"], "MariaDB")))(s)
+				},
+			},
+		},
+	})
+}
+
+func TestReadServerVersion(t *testing.T) {
+	cases := []struct {
+		banner  string
+		version string
+		mariaDB bool
+	}{
+		{"8.0.34-0ubuntu0.22.04.1", "8.0.34", false},
+		{"5.5.5-10.6.12-MariaDB-log", "10.6.12", true},
+	}
+	for _, c := range cases {
+		banner := c.banner
+		server := newFakeServer(t, func(query string) fakeResult {
+			switch query {
+			case "SELECT @@GLOBAL.version":
+				return fakeRow("@@GLOBAL.version", banner)
+			case "SELECT @@GLOBAL.basedir":
+				return fakeRow("@@GLOBAL.basedir", "/usr/")
+			case "SELECT AURORA_VERSION()":
+				return fakeError(unknownFunctionErr, "FUNCTION AURORA_VERSION does not exist")
+			}
+			return fakeDefaultResult(query)
+		})
+		conf, err := testProviderConfigure(t, map[string]interface{}{"endpoint": server.addr()})
+		if err != nil {
+			t.Fatalf("providerConfigure returned %s", err)
+		}
+
+		d := schema.TestResourceDataRaw(t, DataSourceServerVersion().Schema, map[string]interface{}{})
+		if err := ReadServerVersion(d, conf); err != nil {
+			t.Fatalf("ReadServerVersion(%q) returned %s", c.banner, err)
+		}
+		if got := d.Get("version").(string); got != c.version {
+			t.Errorf("ReadServerVersion(%q) version = %q, want %q", c.banner, got, c.version)
+		}
+		if got := d.Get("version_string").(string); got != c.banner {
+			t.Errorf("ReadServerVersion(%q) version_string = %q, want %q", c.banner, got, c.banner)
+		}
+		if got := d.Get("is_mariadb").(bool); got != c.mariaDB {
+			t.Errorf("ReadServerVersion(%q) is_mariadb = %t, want %t", c.banner, got, c.mariaDB)
+		}
+	}
+}
+
+func TestReadServerVersionRDS(t *testing.T) {
+	cases := []struct {
+		baseDir       string
+		auroraVersion string
+		rds           bool
+		aurora        bool
+	}{
+		{"/usr/", "", false, false},
+		{"/rdsdbbin/mysql-8.0.34.R1/", "", true, false},
+		{"/rdsdbbin/oscar-8.0.mysql_aurora.3.04.0/", "3.04.0", true, true},
+	}
+	for _, c := range cases {
+		c := c
+		server := newFakeServer(t, func(query string) fakeResult {
+			switch query {
+			case "SELECT @@GLOBAL.basedir":
+				return fakeRow("@@GLOBAL.basedir", c.baseDir)
+			case "SELECT AURORA_VERSION()":
+				if c.auroraVersion == "" {
+					return fakeError(unknownFunctionErr, "FUNCTION AURORA_VERSION does not exist")
+				}
+				return fakeRow("AURORA_VERSION()", c.auroraVersion)
+			}
+			return fakeDefaultResult(query)
+		})
+		conf, err := testProviderConfigure(t, map[string]interface{}{"endpoint": server.addr()})
+		if err != nil {
+			t.Fatalf("providerConfigure returned %s", err)
+		}
+
+		d := schema.TestResourceDataRaw(t, DataSourceServerVersion().Schema, map[string]interface{}{})
+		if err := ReadServerVersion(d, conf); err != nil {
+			t.Fatalf("ReadServerVersion with basedir %q returned %s", c.baseDir, err)
+		}
+		if got := d.Get("is_rds").(bool); got != c.rds {
+			t.Errorf("ReadServerVersion with basedir %q is_rds = %t, want %t", c.baseDir, got, c.rds)
+		}
+		if got := d.Get("is_aurora").(bool); got != c.aurora {
+			t.Errorf("ReadServerVersion with basedir %q is_aurora = %t, want %t", c.baseDir, got, c.aurora)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package mysql_provider
+
+import (
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func DataSourceTables() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"pattern": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tables": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+		Read: ReadTables,
+	}
+}
+
+func ReadTables(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	database := d.Get("database").(string)
+	pattern := d.Get("pattern").(string)
+
+	stmtSQL := "SHOW TABLES FROM " + quoteIdentifier(database)
+	args := []interface{}{}
+	if pattern != "" {
+		stmtSQL += " LIKE ?"
+		args = append(args, pattern)
+	}
+
+	logQuery(stmtSQL)
+	rows, err := db.QueryContext(ctx, stmtSQL, args...)
+	if err != nil {
+		if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == unknownDatabaseErr {
+			return fmt.Errorf("Database %s does not exist", database)
+		}
+		return fmt.Errorf("Error listing tables of %s: %s", database, err)
+	}
+	defer rows.Close()
+
+	tables := []string{}
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return fmt.Errorf("Error listing tables of %s: %s", database, err)
+		}
+		tables = append(tables, table)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("Error listing tables of %s: %s", database, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", database, pattern))
+	d.Set("tables", tables)
+
+	return nil
+}
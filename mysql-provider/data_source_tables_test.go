@@ -0,0 +1,88 @@
+package mysql_provider
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestAccDataSourceTables(t *testing.T) {
+	database := testAccDatabasePrefix + acctest.RandString(8)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccDatabaseCheckDestroy(database),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name = %q
+}
+
+resource "mysql_sql" "tables" {
+  count      = 3
+  name       = element(["orders", "order_items", "users"], count.index)
+  create_sql = "CREATE TABLE ${mysql_database.test.name}.${element(["orders", "order_items", "users"], count.index)} (id INT)"
+  delete_sql = "DROP TABLE ${mysql_database.test.name}.${element(["orders", "order_items", "users"], count.index)}"
+}
+
+data "mysql_tables" "all" {
+  database   = mysql_database.test.name
+  depends_on = [mysql_sql.tables]
+}
+
+data "mysql_tables" "orders" {
+  database   = mysql_database.test.name
+  pattern    = "order%%"
+  depends_on = [mysql_sql.tables]
+}
+`, database),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mysql_tables.all", "tables.#", "3"),
+					resource.TestCheckResourceAttr("data.mysql_tables.orders", "tables.#", "2"),
+					resource.TestCheckResourceAttr("data.mysql_tables.orders", "tables.0", "order_items"),
+					resource.TestCheckResourceAttr("data.mysql_tables.orders", "tables.1", "orders"),
+				),
+			},
+		},
+	})
+}
+
+func TestReadTables(t *testing.T) {
+	server := newFakeServer(t, func(query string) fakeResult {
+		switch query {
+		case "SHOW TABLES FROM `app`":
+			return fakeResult{
+				columns: []fakeColumn{{"Tables_in_app", fakeTypeVarString}},
+				rows:    [][]interface{}{{"orders"}, {"users"}},
+			}
+		case "SHOW TABLES FROM `gone`":
+			return fakeError(unknownDatabaseErr, "Unknown database 'gone'")
+		}
+		return fakeDefaultResult(query)
+	})
+	conf, err := testProviderConfigure(t, map[string]interface{}{"endpoint": server.addr()})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+
+	d := schema.TestResourceDataRaw(t, DataSourceTables().Schema, map[string]interface{}{"database": "app"})
+	if err := ReadTables(d, conf); err != nil {
+		t.Fatalf("ReadTables returned %s", err)
+	}
+	if got, want := d.Get("tables").([]interface{}), []interface{}{"orders", "users"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("tables = %v, want %v", got, want)
+	}
+
+	d = schema.TestResourceDataRaw(t, DataSourceTables().Schema, map[string]interface{}{"database": "gone"})
+	err = ReadTables(d, conf)
+	if err == nil || !strings.Contains(err.Error(), "Database gone does not exist") {
+		t.Errorf("ReadTables of an unknown database returned %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package mysql_provider
+
+import (
+	"fmt"
+	"time"
+)
+
+// mysqlDateTimeLayout is how DATETIME and TIMESTAMP values are sent when the
+// DSN doesn't set parseTime.
+const mysqlDateTimeLayout = "2006-01-02 15:04:05.999999"
+
+// nullDateTime scans DATETIME and TIMESTAMP columns whether or not parseTime
+// is set in the DSN, conn_params is free to turn it on or off.
+type nullDateTime struct {
+	Time  time.Time
+	Valid bool
+}
+
+func (n *nullDateTime) Scan(value interface{}) error {
+	var err error
+	switch v := value.(type) {
+	case nil:
+		n.Time, n.Valid = time.Time{}, false
+		return nil
+	case time.Time:
+		n.Time = v
+	case []byte:
+		n.Time, err = parseDateTime(string(v))
+	case string:
+		n.Time, err = parseDateTime(v)
+	default:
+		return fmt.Errorf("Can't scan %T into a date and time", value)
+	}
+	n.Valid = err == nil
+	return err
+}
+
+func parseDateTime(value string) (time.Time, error) {
+	if value == "0000-00-00 00:00:00" || value == "0000-00-00" {
+		return time.Time{}, nil
+	}
+	if len(value) == len("2006-01-02") {
+		return time.ParseInLocation("2006-01-02", value, time.UTC)
+	}
+	return time.ParseInLocation(mysqlDateTimeLayout, value, time.UTC)
+}
+
+// String formats the value as RFC 3339, or returns "" for NULL.
+func (n nullDateTime) String() string {
+	if !n.Valid {
+		return ""
+	}
+	return n.Time.Format(time.RFC3339)
+}
@@ -0,0 +1,68 @@
+package mysql_provider
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNullDateTimeParseTime(t *testing.T) {
+	server := newFakeServer(t, func(query string) fakeResult {
+		if query == "SELECT `LAST_EXECUTED` FROM `information_schema`.`EVENTS`" {
+			return fakeResult{
+				columns: []fakeColumn{{"LAST_EXECUTED", fakeTypeDateTime}},
+				rows:    [][]interface{}{{"2026-10-14 12:30:45"}, {nil}},
+			}
+		}
+		return fakeDefaultResult(query)
+	})
+
+	for _, parseTime := range []string{"true", "false"} {
+		conf, err := testProviderConfigure(t, map[string]interface{}{
+			"endpoint":    server.addr(),
+			"conn_params": map[string]interface{}{"parseTime": parseTime},
+		})
+		if err != nil {
+			t.Fatalf("providerConfigure returned %s", err)
+		}
+
+		rows, err := conf.connection().Query("SELECT `LAST_EXECUTED` FROM `information_schema`.`EVENTS`")
+		if err != nil {
+			t.Fatalf("Query returned %s", err)
+		}
+		var values []nullDateTime
+		for rows.Next() {
+			var value nullDateTime
+			if err := rows.Scan(&value); err != nil {
+				t.Fatalf("Scan with parseTime=%s returned %s", parseTime, err)
+			}
+			values = append(values, value)
+		}
+		rows.Close()
+
+		expected := time.Date(2026, 10, 14, 12, 30, 45, 0, time.UTC)
+		if len(values) != 2 || !values[0].Valid || !values[0].Time.Equal(expected) || values[1].Valid {
+			t.Errorf("scanned %v with parseTime=%s, want %s and NULL", values, parseTime, expected)
+		}
+		if got := values[0].String(); got != "2026-10-14T12:30:45Z" {
+			t.Errorf("String() with parseTime=%s = %q, want 2026-10-14T12:30:45Z", parseTime, got)
+		}
+	}
+}
+
+func TestParseDateTime(t *testing.T) {
+	cases := map[string]time.Time{
+		"2026-10-14 12:30:45":        time.Date(2026, 10, 14, 12, 30, 45, 0, time.UTC),
+		"2026-10-14 12:30:45.123456": time.Date(2026, 10, 14, 12, 30, 45, 123456000, time.UTC),
+		"2026-10-14":                 time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC),
+		"0000-00-00 00:00:00":        {},
+	}
+	for in, expected := range cases {
+		got, err := parseDateTime(in)
+		if err != nil || !got.Equal(expected) {
+			t.Errorf("parseDateTime(%q) = %s, %v, want %s", in, got, err, expected)
+		}
+	}
+	if _, err := parseDateTime("yesterday"); err == nil {
+		t.Error("parseDateTime(\"yesterday\") returned no error")
+	}
+}
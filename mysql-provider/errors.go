@@ -0,0 +1,75 @@
+package mysql_provider
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+const readOnlyServerErr = 1290
+
+// withReadOnlyHint wraps the Create, Update and Delete functions of the
+// resources so a write refused by a read-only server, typically a replica,
+// says so instead of failing with the raw server error.
+func withReadOnlyHint(resources map[string]*schema.Resource) map[string]*schema.Resource {
+	for _, r := range resources {
+		r.Create = readOnlyHint(r.Create)
+		r.Update = readOnlyHint(r.Update)
+		r.Delete = readOnlyHint(r.Delete)
+	}
+	return resources
+}
+
+func readOnlyHint(f func(*schema.ResourceData, interface{}) error) func(*schema.ResourceData, interface{}) error {
+	if f == nil {
+		return nil
+	}
+	return func(d *schema.ResourceData, meta interface{}) error {
+		err := f(d, meta)
+		if err != nil && isReadOnlyError(err) {
+			return fmt.Errorf("%s\n\nThe server is running in read-only mode, which usually means the provider is connected to a replica. Point endpoint at the primary to make changes.", err)
+		}
+		return err
+	}
+}
+
+// isReadOnlyError also recognizes the server error once it was formatted into
+// one of the resources' own errors. Error 1290 is raised for any option that
+// prevents a statement, e.g. --secure-file-priv, so the message has to name
+// read-only or super-read-only as well.
+func isReadOnlyError(err error) bool {
+	if mysqlErr, ok := err.(*mysql.MySQLError); ok {
+		return mysqlErr.Number == readOnlyServerErr && strings.Contains(mysqlErr.Message, "read-only")
+	}
+	msg := err.Error()
+	return strings.Contains(msg, fmt.Sprintf("Error %d:", readOnlyServerErr)) && strings.Contains(msg, "read-only")
+}
+
+// scrubCredentials removes the password from an error message, in case the
+// driver echoed it back as part of the DSN.
+func scrubCredentials(err error, passwd string) error {
+	if err == nil || passwd == "" {
+		return err
+	}
+	msg := strings.Replace(err.Error(), passwd, "<redacted>", -1)
+	msg = strings.Replace(msg, url.QueryEscape(passwd), "<redacted>", -1)
+	return fmt.Errorf("%s", msg)
+}
+
+// xProtocolPort is the default port of the X Protocol, which the driver
+// doesn't speak.
+const xProtocolPort = "33060"
+
+// isXProtocolMismatch reports whether a failed connection attempt likely
+// reached an X Protocol listener: either the endpoint uses its port, or the
+// greeting couldn't be parsed as a classic protocol handshake.
+func isXProtocolMismatch(endpoint string, err error) bool {
+	if _, port, splitErr := net.SplitHostPort(endpoint); splitErr == nil && port == xProtocolPort {
+		return true
+	}
+	return err == mysql.ErrMalformPkt
+}
@@ -0,0 +1,132 @@
+package mysql_provider
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestIsReadOnlyError(t *testing.T) {
+	readOnly := &mysql.MySQLError{Number: readOnlyServerErr, Message: "The MySQL server is running with the --read-only option so it cannot execute this statement"}
+	secureFile := &mysql.MySQLError{Number: readOnlyServerErr, Message: "The MySQL server is running with the --secure-file-priv option so it cannot execute this statement"}
+
+	cases := []struct {
+		err      error
+		expected bool
+	}{
+		{readOnly, true},
+		{secureFile, false},
+		{fmt.Errorf("Error creating database app: %s", readOnly), true},
+		{fmt.Errorf("Error creating database app: %s", secureFile), false},
+		{&mysql.MySQLError{Number: 1045, Message: "Access denied"}, false},
+		{errors.New("read-only"), false},
+	}
+	for _, c := range cases {
+		if got := isReadOnlyError(c.err); got != c.expected {
+			t.Errorf("isReadOnlyError(%q) = %t, want %t", c.err, got, c.expected)
+		}
+	}
+}
+
+func TestReadOnlyHint(t *testing.T) {
+	server := newFakeServer(t, func(query string) fakeResult {
+		if strings.HasPrefix(query, "CREATE DATABASE") {
+			return fakeError(readOnlyServerErr, "The MySQL server is running with the --read-only option so it cannot execute this statement")
+		}
+		return fakeDefaultResult(query)
+	})
+	conf, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint":           server.addr(),
+		"interpolate_params": true,
+	})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+
+	r := Provider().(*schema.Provider).ResourcesMap["mysql_database"]
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"name": "app"})
+	err = r.Create(d, conf)
+	if err == nil {
+		t.Fatal("Create on a read-only server succeeded")
+	}
+	if !strings.Contains(err.Error(), "connected to a replica") {
+		t.Errorf("Create on a read-only server returned %q, want the read-only hint", err)
+	}
+}
+
+func TestScrubCredentials(t *testing.T) {
+	err := scrubCredentials(errors.New("dial app:p@ss w0rd@tcp(db:3306)/ and app:p%40ss+w0rd@tcp"), "p@ss w0rd")
+	if strings.Contains(err.Error(), "p@ss") || strings.Contains(err.Error(), "p%40ss") {
+		t.Errorf("scrubCredentials left the password in %q", err)
+	}
+	if strings.Count(err.Error(), "<redacted>") != 2 {
+		t.Errorf("scrubCredentials(...) = %q, want both spellings redacted", err)
+	}
+
+	if scrubCredentials(nil, "secret") != nil {
+		t.Error("scrubCredentials(nil) is not nil")
+	}
+	plain := errors.New("connection refused")
+	if scrubCredentials(plain, "") != plain {
+		t.Error("scrubCredentials without a password changed the error")
+	}
+}
+
+func TestProviderConfigureScrubsPassword(t *testing.T) {
+	server := newFakeServer(t, nil)
+	passwd := "s3cr3t p@ss"
+	// A server or proxy echoing the DSN back, as some do on errors.
+	server.rejectLogins(&mysql.MySQLError{Number: 1045, Message: "Access denied for tf:" + passwd + "@tcp(" + server.addr() + ")/"})
+
+	_, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint": server.addr(),
+		"password": passwd,
+	})
+	if err == nil {
+		t.Fatal("providerConfigure with rejected logins succeeded")
+	}
+	if strings.Contains(err.Error(), passwd) {
+		t.Errorf("providerConfigure returned the password in %q", err)
+	}
+}
+
+func TestIsXProtocolMismatch(t *testing.T) {
+	connRefused := errors.New("connection refused")
+	cases := []struct {
+		endpoint string
+		err      error
+		expected bool
+	}{
+		{"localhost:33060", connRefused, true},
+		{"localhost:3306", mysql.ErrMalformPkt, true},
+		{"localhost:3306", connRefused, false},
+		{"/tmp/mysql.sock", connRefused, false},
+	}
+	for _, c := range cases {
+		if got := isXProtocolMismatch(c.endpoint, c.err); got != c.expected {
+			t.Errorf("isXProtocolMismatch(%q, %q) = %t, want %t", c.endpoint, c.err, got, c.expected)
+		}
+	}
+}
+
+func TestProviderConfigureXProtocolWarning(t *testing.T) {
+	testSetenv(t, "MYSQL_ENDPOINT", "")
+	if listener, err := net.Listen("tcp", "127.0.0.1:33060"); err != nil {
+		t.Skipf("port 33060 is in use: %s", err)
+	} else {
+		listener.Close()
+	}
+	output := testCaptureLog(t)
+
+	if _, err := testProviderConfigure(t, map[string]interface{}{"endpoint": "127.0.0.1:33060"}); err == nil {
+		t.Fatal("providerConfigure connected to a closed port")
+	}
+	if !strings.Contains(output.String(), "looks like an X Protocol endpoint") {
+		t.Errorf("providerConfigure didn't warn about the X Protocol port, logged %q", output.String())
+	}
+}
@@ -0,0 +1,84 @@
+package mysql_provider
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"log"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const (
+	lockWaitTimeoutErr = 1205
+	lockNowaitErr      = 3572
+	serverGoneErr      = 2006
+
+	lockWaitRetryTimeout = 5 * time.Minute
+	lockWaitMaxBackoff   = 5 * time.Second
+)
+
+// execer is implemented by both *sql.DB and *sql.Conn.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// execContext runs the statement, retrying with exponential backoff while it
+// fails on a lock wait, as parallel DDL on the same objects is bound to. The
+// retries end with the context, or after lockWaitRetryTimeout if it has no
+// deadline. A statement run through the pool on a connection the server had
+// already closed, e.g. because it restarted, is retried once on a fresh
+// connection, reconnecting to the server first when ctx comes from
+// statementContext.
+func execContext(ctx context.Context, db execer, stmtSQL string, args ...interface{}) (sql.Result, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, lockWaitRetryTimeout)
+		defer cancel()
+	}
+
+	backoff := 100 * time.Millisecond
+	reconnected := false
+	for {
+		result, err := db.ExecContext(ctx, stmtSQL, args...)
+		if _, pooled := db.(*sql.DB); pooled && !reconnected && isConnectionLostError(err) {
+			log.Printf("[WARN] Lost connection running %q, retrying on a new connection: %s", redactSQL(stmtSQL), err)
+			reconnected = true
+			if conf, ok := ctx.Value(configurationContextKey{}).(*MySQLConfiguration); ok {
+				db = conf.connection()
+			}
+			continue
+		}
+		if err == nil || !isLockWaitError(err) {
+			return result, err
+		}
+
+		log.Printf("[WARN] Lock wait on %q, retrying in %s: %s", redactSQL(stmtSQL), backoff, err)
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > lockWaitMaxBackoff {
+			backoff = lockWaitMaxBackoff
+		}
+	}
+}
+
+func isLockWaitError(err error) bool {
+	mysqlErr, ok := err.(*mysql.MySQLError)
+	return ok && (mysqlErr.Number == lockWaitTimeoutErr || mysqlErr.Number == lockNowaitErr)
+}
+
+// isConnectionLostError recognizes the errors of a connection that was gone
+// before the statement was sent, so running it again can't apply it twice.
+// A connection lost mid-statement, mysql.ErrInvalidConn or error 2013, may
+// have run it already and is not retried.
+func isConnectionLostError(err error) bool {
+	if err == driver.ErrBadConn {
+		return true
+	}
+	mysqlErr, ok := err.(*mysql.MySQLError)
+	return ok && mysqlErr.Number == serverGoneErr
+}
@@ -0,0 +1,157 @@
+package mysql_provider
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestStatementTimeout(t *testing.T) {
+	server := newFakeServer(t, func(query string) fakeResult {
+		if strings.HasPrefix(query, "DO SLEEP") {
+			time.Sleep(3 * time.Second)
+		}
+		return fakeDefaultResult(query)
+	})
+	conf, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint":              server.addr(),
+		"statement_timeout_sec": 1,
+	})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+
+	ctx, cancel := conf.statementContext()
+	defer cancel()
+	start := time.Now()
+	_, err = execContext(ctx, conf.connection(), "DO SLEEP(3)")
+	if err != context.DeadlineExceeded {
+		t.Errorf("execContext returned %v, want %s", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("execContext returned after %s, want it cancelled after 1s", elapsed)
+	}
+}
+
+func TestStatementContextWithoutTimeout(t *testing.T) {
+	conf := &MySQLConfiguration{}
+	ctx, cancel := conf.statementContext()
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("statementContext without statement_timeout_sec has a deadline")
+	}
+}
+
+func TestIsLockWaitError(t *testing.T) {
+	cases := []struct {
+		err      error
+		expected bool
+	}{
+		{&mysql.MySQLError{Number: lockWaitTimeoutErr}, true},
+		{&mysql.MySQLError{Number: lockNowaitErr}, true},
+		{&mysql.MySQLError{Number: serverGoneErr}, false},
+		{driver.ErrBadConn, false},
+	}
+	for _, c := range cases {
+		if got := isLockWaitError(c.err); got != c.expected {
+			t.Errorf("isLockWaitError(%v) = %t, want %t", c.err, got, c.expected)
+		}
+	}
+}
+
+func TestExecContextRetriesLockWait(t *testing.T) {
+	var mu sync.Mutex
+	attempts := 0
+	server := newFakeServer(t, func(query string) fakeResult {
+		if query != "CREATE DATABASE `app`" {
+			return fakeDefaultResult(query)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if attempts++; attempts < 3 {
+			return fakeError(lockWaitTimeoutErr, "Lock wait timeout exceeded; try restarting transaction")
+		}
+		return fakeResult{}
+	})
+	conf, err := testProviderConfigure(t, map[string]interface{}{"endpoint": server.addr()})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+
+	if _, err := execContext(context.Background(), conf.connection(), "CREATE DATABASE `app`"); err != nil {
+		t.Errorf("execContext returned %s, want the lock wait retried", err)
+	}
+	mu.Lock()
+	if attempts != 3 {
+		t.Errorf("execContext ran the statement %d times, want 3", attempts)
+	}
+	attempts = -100
+	mu.Unlock()
+
+	// The retries end with the context.
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = execContext(ctx, conf.connection(), "CREATE DATABASE `app`")
+	if !isLockWaitError(err) {
+		t.Errorf("execContext past its deadline returned %v, want the lock wait error", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("execContext kept retrying for %s past a 200ms deadline", elapsed)
+	}
+}
+
+func TestExecContextReconnects(t *testing.T) {
+	testSetenv(t, "MYSQL_ENDPOINT", "")
+	standby := newFakeServer(t, nil)
+	var primary *fakeServer
+	primary = newFakeServer(t, func(query string) fakeResult {
+		if query == "CREATE DATABASE `app`" {
+			// The primary goes away for good, only a new connection to
+			// the standby can run the statement.
+			primary.listener.Close()
+			return fakeResult{err: &mysql.MySQLError{Number: serverGoneErr, Message: "MySQL server has gone away"}, hangUp: true}
+		}
+		return fakeDefaultResult(query)
+	})
+	conf, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoints": []interface{}{primary.addr(), standby.addr()},
+	})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+
+	ctx, cancel := conf.statementContext()
+	defer cancel()
+	if _, err := execContext(ctx, conf.connection(), "CREATE DATABASE `app`"); err != nil {
+		t.Fatalf("execContext after the server went away returned %s", err)
+	}
+	if !fakeQueriesContain(standby.receivedQueries(), "CREATE DATABASE `app`") {
+		t.Errorf("the statement wasn't retried on the standby, it received %q", standby.receivedQueries())
+	}
+}
+
+func TestIsConnectionLostError(t *testing.T) {
+	cases := []struct {
+		err      error
+		expected bool
+	}{
+		{driver.ErrBadConn, true},
+		{&mysql.MySQLError{Number: serverGoneErr}, true},
+		{mysql.ErrInvalidConn, false},
+		{&mysql.MySQLError{Number: 2013}, false},
+		{&mysql.MySQLError{Number: lockWaitTimeoutErr}, false},
+		{errors.New("connection refused"), false},
+	}
+	for _, c := range cases {
+		if got := isConnectionLostError(c.err); got != c.expected {
+			t.Errorf("isConnectionLostError(%v) = %t, want %t", c.err, got, c.expected)
+		}
+	}
+}
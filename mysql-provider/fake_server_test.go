@@ -0,0 +1,357 @@
+package mysql_provider
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// fakeServerVersion is the version the fake server greets clients with.
+const fakeServerVersion = "8.0.30-fake"
+
+// fakeResult is the reply of the fake server to a query. Without columns it is
+// an OK packet, with err set an error packet. With hangUp set the server
+// closes the connection after replying.
+type fakeResult struct {
+	columns []fakeColumn
+	rows    [][]interface{}
+	err     *mysql.MySQLError
+	hangUp  bool
+}
+
+// fakeColumn is a result set column, kind is one of the protocol's field
+// types, e.g. fakeTypeVarString.
+type fakeColumn struct {
+	name string
+	kind byte
+}
+
+const (
+	fakeTypeLongLong  = 0x08
+	fakeTypeDateTime  = 0x0c
+	fakeTypeVarString = 0xfd
+)
+
+// fakeServer speaks just enough of the classic MySQL protocol for the provider
+// to connect, ping and run text protocol statements, so the connection code
+// can be tested without a server. Every client is accepted whatever its
+// credentials are.
+type fakeServer struct {
+	listener net.Listener
+	handler  func(query string) fakeResult
+
+	mu       sync.Mutex
+	queries  []string
+	conns    map[net.Conn]bool
+	loginErr *mysql.MySQLError
+	logins   int
+}
+
+// newFakeServer starts a fake server on a random local port, answering the
+// queries it receives with handler. A nil handler answers every query with
+// fakeDefaultResult.
+func newFakeServer(t *testing.T, handler func(query string) fakeResult) *fakeServer {
+	return newFakeServerListening(t, "tcp", "127.0.0.1:0", handler)
+}
+
+// newFakeServerListening starts a fake server on the given network address,
+// e.g. a unix socket.
+func newFakeServerListening(t *testing.T, network, address string, handler func(query string) fakeResult) *fakeServer {
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		t.Fatalf("Could not start the fake server: %s", err)
+	}
+	if handler == nil {
+		handler = fakeDefaultResult
+	}
+	s := &fakeServer{listener: listener, handler: handler, conns: map[net.Conn]bool{}}
+	go s.serve()
+	t.Cleanup(s.close)
+	return s
+}
+
+// fakeDefaultResult answers the queries the provider runs while configuring,
+// everything else succeeds without a result set.
+func fakeDefaultResult(query string) fakeResult {
+	switch query {
+	case "SELECT @@max_allowed_packet":
+		return fakeRow("@@max_allowed_packet", "4194304")
+	case "SELECT @@SESSION.sql_mode":
+		return fakeRow("@@SESSION.sql_mode", "STRICT_TRANS_TABLES")
+	case "SELECT @@GLOBAL.version":
+		return fakeRow("@@GLOBAL.version", fakeServerVersion)
+	}
+	return fakeResult{}
+}
+
+// fakeRow is a result set of a single string column and row.
+func fakeRow(column string, value interface{}) fakeResult {
+	return fakeResult{
+		columns: []fakeColumn{{name: column, kind: fakeTypeVarString}},
+		rows:    [][]interface{}{{value}},
+	}
+}
+
+// fakeError is an error packet, as the server sends it.
+func fakeError(number uint16, message string) fakeResult {
+	return fakeResult{err: &mysql.MySQLError{Number: number, Message: message}}
+}
+
+func (s *fakeServer) addr() string {
+	return s.listener.Addr().String()
+}
+
+// receivedQueries returns the queries received so far, in order.
+func (s *fakeServer) receivedQueries() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.queries...)
+}
+
+// dropConnections closes every open client connection, like a server restart
+// would.
+func (s *fakeServer) dropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.conns {
+		conn.Close()
+		delete(s.conns, conn)
+	}
+}
+
+// rejectLogins fails every following login with err, e.g. an access denied.
+func (s *fakeServer) rejectLogins(err *mysql.MySQLError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loginErr = err
+}
+
+// loginAttempts returns the number of logins the server received so far.
+func (s *fakeServer) loginAttempts() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.logins
+}
+
+func (s *fakeServer) close() {
+	s.listener.Close()
+	s.dropConnections()
+}
+
+func (s *fakeServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns[conn] = true
+		s.mu.Unlock()
+		go s.serveConn(conn)
+	}
+}
+
+func (s *fakeServer) serveConn(conn net.Conn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	if err := writeFakePacket(conn, 0, fakeHandshake()); err != nil {
+		return
+	}
+	if _, _, err := readFakePacket(conn); err != nil {
+		return
+	}
+	s.mu.Lock()
+	s.logins++
+	loginErr := s.loginErr
+	s.mu.Unlock()
+	if loginErr != nil {
+		writeFakePacket(conn, 2, fakeErrorPacket(loginErr))
+		return
+	}
+	if err := writeFakePacket(conn, 2, fakeOK()); err != nil {
+		return
+	}
+
+	for {
+		_, data, err := readFakePacket(conn)
+		if err != nil || len(data) == 0 {
+			return
+		}
+		switch data[0] {
+		case 0x01: // COM_QUIT
+			return
+		case 0x02, 0x0e: // COM_INIT_DB, COM_PING
+			err = writeFakePacket(conn, 1, fakeOK())
+		case 0x03: // COM_QUERY
+			query := string(data[1:])
+			s.mu.Lock()
+			s.queries = append(s.queries, query)
+			s.mu.Unlock()
+			result := s.handler(query)
+			if err = writeFakeResult(conn, result); err == nil && result.hangUp {
+				return
+			}
+		default:
+			err = writeFakePacket(conn, 1, fakeErrorPacket(&mysql.MySQLError{Number: 1047, Message: "Unknown command"}))
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func fakeHandshake() []byte {
+	// CLIENT_LONG_PASSWORD, CLIENT_PROTOCOL_41, CLIENT_TRANSACTIONS,
+	// CLIENT_SECURE_CONNECTION and CLIENT_PLUGIN_AUTH.
+	var capabilities uint32 = 0x00000001 | 0x00000200 | 0x00002000 | 0x00008000 | 0x00080000
+
+	data := []byte{10}
+	data = append(data, fakeServerVersion...)
+	data = append(data, 0)
+	data = append(data, 1, 0, 0, 0)
+	data = append(data, "abcdefgh"...)
+	data = append(data, 0)
+	data = append(data, byte(capabilities), byte(capabilities>>8))
+	data = append(data, 0x21, 0x02, 0x00)
+	data = append(data, byte(capabilities>>16), byte(capabilities>>24))
+	data = append(data, 21)
+	data = append(data, make([]byte, 10)...)
+	data = append(data, "ijklmnopqrst"...)
+	data = append(data, 0)
+	data = append(data, cachingSHA2Plugin...)
+	return append(data, 0)
+}
+
+func fakeOK() []byte {
+	return []byte{0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00}
+}
+
+func fakeEOF() []byte {
+	return []byte{0xfe, 0x00, 0x00, 0x02, 0x00}
+}
+
+func fakeErrorPacket(err *mysql.MySQLError) []byte {
+	data := []byte{0xff, byte(err.Number), byte(err.Number >> 8)}
+	data = append(data, "#HY000"...)
+	return append(data, err.Message...)
+}
+
+func writeFakeResult(conn net.Conn, result fakeResult) error {
+	if result.err != nil {
+		return writeFakePacket(conn, 1, fakeErrorPacket(result.err))
+	}
+	if len(result.columns) == 0 {
+		return writeFakePacket(conn, 1, fakeOK())
+	}
+
+	packets := [][]byte{{byte(len(result.columns))}}
+	for _, column := range result.columns {
+		var data []byte
+		for _, s := range []string{"def", "", "", "", column.name, column.name} {
+			data = appendFakeString(data, s)
+		}
+		data = append(data, 0x0c, 0x21, 0x00, 0xff, 0x00, 0x00, 0x00, column.kind, 0x00, 0x00, 0x00, 0x00, 0x00)
+		packets = append(packets, data)
+	}
+	packets = append(packets, fakeEOF())
+	for _, row := range result.rows {
+		var data []byte
+		for _, value := range row {
+			if value == nil {
+				data = append(data, 0xfb)
+				continue
+			}
+			data = appendFakeString(data, value.(string))
+		}
+		packets = append(packets, data)
+	}
+	packets = append(packets, fakeEOF())
+
+	for i, data := range packets {
+		if err := writeFakePacket(conn, byte(i+1), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendFakeString appends a length encoded string shorter than 251 bytes.
+func appendFakeString(data []byte, s string) []byte {
+	return append(append(data, byte(len(s))), s...)
+}
+
+func writeFakePacket(conn net.Conn, seq byte, data []byte) error {
+	header := []byte{byte(len(data)), byte(len(data) >> 8), byte(len(data) >> 16), seq}
+	_, err := conn.Write(append(header, data...))
+	return err
+}
+
+func readFakePacket(conn net.Conn) (byte, []byte, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return 0, nil, err
+	}
+	data := make([]byte, binary.LittleEndian.Uint32(append(header[:3:3], 0)))
+	if _, err := io.ReadFull(conn, data); err != nil {
+		return 0, nil, err
+	}
+	return header[3], data, nil
+}
+
+// testProviderConfigure runs providerConfigure against the given provider
+// attributes. Unless raw sets them, credentials are filled in and failed
+// connections aren't retried.
+func testProviderConfigure(t *testing.T, raw map[string]interface{}) (*MySQLConfiguration, error) {
+	attributes := map[string]interface{}{
+		"username":                  "tf",
+		"password":                  "tf",
+		"connect_retry_timeout_sec": 0,
+	}
+	for k, v := range raw {
+		attributes[k] = v
+	}
+	d := schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, attributes)
+	meta, err := providerConfigure(d)
+	if err != nil {
+		return nil, err
+	}
+	conf := meta.(*MySQLConfiguration)
+	t.Cleanup(func() { conf.Db.Close() })
+	return conf, nil
+}
+
+// fakeQueriesContain reports whether one of the queries contains s.
+func fakeQueriesContain(queries []string, s string) bool {
+	for _, query := range queries {
+		if strings.Contains(query, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// testFakeConfiguration is a configuration connecting to the given endpoints,
+// as providerConfigure would set it up.
+func testFakeConfiguration(endpoints ...string) *MySQLConfiguration {
+	config := mysql.NewConfig()
+	config.User = "tf"
+	config.Net = "tcp"
+	config.Addr = endpoints[0]
+	return &MySQLConfiguration{
+		Config:       config,
+		MaxOpenConns: 2,
+		MaxIdleConns: 2,
+		Endpoints:    endpoints,
+	}
+}
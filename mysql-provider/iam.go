@@ -0,0 +1,34 @@
+package mysql_provider
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/rds/rdsutils"
+)
+
+// rdsAuthTokenFunc returns a function generating short-lived RDS IAM
+// authentication tokens for the given user and endpoint. Tokens are only valid
+// for 15 minutes, so a fresh one is built on every connection attempt.
+func rdsAuthTokenFunc(region string, user string) (func(endpoint string) (string, error), error) {
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            aws.Config{Region: aws.String(region)},
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Could not create AWS session: %s", err)
+	}
+
+	if aws.StringValue(sess.Config.Region) == "" {
+		return nil, fmt.Errorf("aws_region must be set when iam_database_authentication is enabled")
+	}
+
+	return func(endpoint string) (string, error) {
+		token, err := rdsutils.BuildAuthToken(endpoint, aws.StringValue(sess.Config.Region), user, sess.Config.Credentials)
+		if err != nil {
+			return "", fmt.Errorf("Could not build RDS auth token: %s", err)
+		}
+		return token, nil
+	}, nil
+}
@@ -0,0 +1,96 @@
+package mysql_provider
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestMySQLConnectAuthToken(t *testing.T) {
+	server := newFakeServer(t, nil)
+
+	conf := testFakeConfiguration(server.addr())
+	var requested []string
+	conf.AuthTokenFunc = func(endpoint string) (string, error) {
+		requested = append(requested, endpoint)
+		return fmt.Sprintf("token-%d", len(requested)), nil
+	}
+
+	for i := 1; i <= 2; i++ {
+		db, err := mySQLConnect(conf)
+		if err != nil {
+			t.Fatalf("mySQLConnect returned %s", err)
+		}
+		db.Close()
+
+		token := fmt.Sprintf("token-%d", i)
+		if conf.Config.Passwd != token {
+			t.Errorf("connection %d used password %q, want %q", i, conf.Config.Passwd, token)
+		}
+		if dsn := conf.Config.FormatDSN(); !strings.Contains(dsn, ":"+token+"@") {
+			t.Errorf("DSN %q doesn't use %s", dsn, token)
+		}
+	}
+	if len(requested) != 2 || requested[0] != server.addr() {
+		t.Errorf("tokens were requested for %q, want one for %s per connection", requested, server.addr())
+	}
+}
+
+func TestMySQLConnectAuthTokenError(t *testing.T) {
+	server := newFakeServer(t, nil)
+
+	conf := testFakeConfiguration(server.addr())
+	conf.AuthTokenFunc = func(endpoint string) (string, error) {
+		return "", fmt.Errorf("Could not build RDS auth token: no credentials")
+	}
+	if _, err := mySQLConnect(conf); err == nil || !strings.Contains(err.Error(), "no credentials") {
+		t.Errorf("mySQLConnect returned %v, want the token error", err)
+	}
+}
+
+func TestProviderConfigureIAMRequiresTLS(t *testing.T) {
+	_, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint":                    "127.0.0.1:3306",
+		"password":                    "",
+		"tls":                         "false",
+		"iam_database_authentication": true,
+		"aws_region":                  "eu-west-1",
+	})
+	if err == nil || !strings.Contains(err.Error(), "requires tls") {
+		t.Errorf("providerConfigure returned %v, want an error asking for tls", err)
+	}
+}
+
+func TestRDSAuthTokenFunc(t *testing.T) {
+	testSetenv(t, "AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	testSetenv(t, "AWS_SECRET_ACCESS_KEY", "secret")
+	testSetenv(t, "AWS_SESSION_TOKEN", "")
+
+	tokenFunc, err := rdsAuthTokenFunc("eu-west-1", "app")
+	if err != nil {
+		t.Fatalf("rdsAuthTokenFunc returned %s", err)
+	}
+	token, err := tokenFunc("db.example.com:3306")
+	if err != nil {
+		t.Fatalf("Building the token returned %s", err)
+	}
+	for _, part := range []string{"db.example.com:3306?Action=connect", "DBUser=app", "X-Amz-Credential=AKIDEXAMPLE%2F", "eu-west-1%2Frds-db"} {
+		if !strings.Contains(token, part) {
+			t.Errorf("Token %q is missing %q", token, part)
+		}
+	}
+}
+
+// testSetenv sets an environment variable for the duration of the test.
+func testSetenv(t *testing.T, name string, value string) {
+	previous, ok := os.LookupEnv(name)
+	os.Setenv(name, value)
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(name, previous)
+		} else {
+			os.Unsetenv(name)
+		}
+	})
+}
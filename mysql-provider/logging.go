@@ -0,0 +1,26 @@
+package mysql_provider
+
+import (
+	"log"
+	"regexp"
+)
+
+// secretClauseRegexp matches the quoted secret of IDENTIFIED BY/AS and
+// PASSWORD clauses, including escaped and doubled quotes inside it.
+var secretClauseRegexp = regexp.MustCompile(`(?i)((?:IDENTIFIED\s+(?:WITH\s+\S+\s+)?(?:BY|AS)|PASSWORD(?:\s+FOR\s+\S+)?\s*=?)\s*)'(?:[^'\\]|\\.|'')*'`)
+
+// redactSQL masks the secrets in a statement before it is logged.
+func redactSQL(stmtSQL string) string {
+	return secretClauseRegexp.ReplaceAllString(stmtSQL, "$1'<redacted>'")
+}
+
+// logStatement logs a statement about to be executed at DEBUG level, with
+// any secrets redacted.
+func logStatement(stmtSQL string) {
+	log.Printf("[DEBUG] Executing statement: %s", redactSQL(stmtSQL))
+}
+
+// logQuery is logStatement for queries.
+func logQuery(stmtSQL string) {
+	log.Printf("[DEBUG] Executing query: %s", redactSQL(stmtSQL))
+}
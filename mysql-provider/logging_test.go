@@ -0,0 +1,34 @@
+package mysql_provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedactSQL(t *testing.T) {
+	cases := map[string]string{
+		"CREATE USER 'app'@'%' IDENTIFIED BY 'secret'":                            "CREATE USER 'app'@'%' IDENTIFIED BY '<redacted>'",
+		"ALTER USER 'app'@'%' IDENTIFIED WITH `mysql_native_password` BY 'it''s'": "ALTER USER 'app'@'%' IDENTIFIED WITH `mysql_native_password` BY '<redacted>'",
+		"CREATE USER 'app'@'%' IDENTIFIED WITH auth_plugin AS '*ABCDEF'":          "CREATE USER 'app'@'%' IDENTIFIED WITH auth_plugin AS '<redacted>'",
+		`SET PASSWORD FOR 'app'@'%' = 'a\'b'`:                                     "SET PASSWORD FOR 'app'@'%' = '<redacted>'",
+		"identified by 'lower'":                                                   "identified by '<redacted>'",
+		"SELECT 'not a secret'":                                                   "SELECT 'not a secret'",
+	}
+	for in, expected := range cases {
+		if got := redactSQL(in); got != expected {
+			t.Errorf("redactSQL(%q) = %q, want %q", in, got, expected)
+		}
+	}
+}
+
+func TestLogStatementRedactsPassword(t *testing.T) {
+	output := testCaptureLog(t)
+	logStatement("CREATE USER 'app'@'%' IDENTIFIED BY 'hunter2'")
+	logged := output.String()
+	if strings.Contains(logged, "hunter2") {
+		t.Errorf("logStatement logged the password: %q", logged)
+	}
+	if !strings.Contains(logged, "[DEBUG]") || !strings.Contains(logged, "IDENTIFIED BY '<redacted>'") {
+		t.Errorf("logStatement logged %q, want the redacted statement at DEBUG", logged)
+	}
+}
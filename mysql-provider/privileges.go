@@ -0,0 +1,103 @@
+package mysql_provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+)
+
+const (
+	verifyPrivilegesWarn  = "warn"
+	verifyPrivilegesError = "error"
+)
+
+// requiredPrivileges are the global privileges the provider's resources rely
+// on: managing databases, accounts and their grants.
+var requiredPrivileges = []string{
+	"CREATE",
+	"DROP",
+	"ALTER",
+	"CREATE USER",
+	"GRANT OPTION",
+}
+
+// verifyPrivileges checks the global grants of the connected account for
+// requiredPrivileges. Missing ones are logged, or returned as an error when
+// mode is verifyPrivilegesError.
+func verifyPrivileges(db *sql.DB, mode string) error {
+	granted, err := currentGlobalPrivileges(db)
+	if err != nil {
+		return fmt.Errorf("Could not verify privileges: %s", err)
+	}
+
+	var missing []string
+	for _, privilege := range requiredPrivileges {
+		if granted[privilege] || (granted["ALL PRIVILEGES"] && privilege != "GRANT OPTION") {
+			continue
+		}
+		missing = append(missing, privilege)
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	if mode == verifyPrivilegesError {
+		return fmt.Errorf("The configured user is missing the global privileges: %s", strings.Join(missing, ", "))
+	}
+	log.Printf("[WARN] The configured user is missing the global privileges: %s", strings.Join(missing, ", "))
+	return nil
+}
+
+// currentGlobalPrivileges returns the privileges granted ON *.* to the
+// current account, as found in SHOW GRANTS.
+func currentGlobalPrivileges(db *sql.DB) (map[string]bool, error) {
+	stmtSQL := "SHOW GRANTS FOR CURRENT_USER()"
+	logQuery(stmtSQL)
+
+	rows, err := db.Query(stmtSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	granted := map[string]bool{}
+	for rows.Next() {
+		var grant string
+		if err := rows.Scan(&grant); err != nil {
+			return nil, err
+		}
+
+		on := strings.Index(grant, " ON *.* TO ")
+		if !strings.HasPrefix(grant, "GRANT ") || on == -1 {
+			continue
+		}
+		for _, privilege := range strings.Split(grant[len("GRANT "):on], ",") {
+			granted[strings.ToUpper(strings.TrimSpace(privilege))] = true
+		}
+		if strings.HasSuffix(grant, "WITH GRANT OPTION") {
+			granted["GRANT OPTION"] = true
+		}
+	}
+
+	return granted, rows.Err()
+}
+
+// flushPrivileges runs FLUSH PRIVILEGES when the provider's
+// flush_privileges_after is set.
+func flushPrivileges(ctx context.Context, conf *MySQLConfiguration, db *sql.DB) error {
+	if !conf.FlushPrivilegesAfter {
+		return nil
+	}
+
+	stmtSQL := "FLUSH PRIVILEGES"
+	logStatement(stmtSQL)
+
+	_, err := execContext(ctx, db, stmtSQL)
+	if err != nil {
+		return fmt.Errorf("Error flushing privileges: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package mysql_provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// testGrantsServer is a fake server granting the current user grants.
+func testGrantsServer(t *testing.T, grants ...string) *fakeServer {
+	return newFakeServer(t, func(query string) fakeResult {
+		if query != "SHOW GRANTS FOR CURRENT_USER()" {
+			return fakeDefaultResult(query)
+		}
+		result := fakeResult{columns: []fakeColumn{{"Grants for tf@%", fakeTypeVarString}}}
+		for _, grant := range grants {
+			result.rows = append(result.rows, []interface{}{grant})
+		}
+		return result
+	})
+}
+
+func TestVerifyPrivileges(t *testing.T) {
+	restricted := testGrantsServer(t,
+		"GRANT SELECT, CREATE ON *.* TO `tf`@`%`",
+		"GRANT ALL PRIVILEGES ON `app`.* TO `tf`@`%`",
+	)
+
+	output := testCaptureLog(t)
+	if _, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint":          restricted.addr(),
+		"verify_privileges": verifyPrivilegesWarn,
+	}); err != nil {
+		t.Fatalf("providerConfigure with verify_privileges = %q returned %s", verifyPrivilegesWarn, err)
+	}
+	expected := "[WARN] The configured user is missing the global privileges: DROP, ALTER, CREATE USER, GRANT OPTION"
+	if !strings.Contains(output.String(), expected) {
+		t.Errorf("providerConfigure logged %q, want it to contain %q", output.String(), expected)
+	}
+
+	_, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint":          restricted.addr(),
+		"verify_privileges": verifyPrivilegesError,
+	})
+	if err == nil || !strings.Contains(err.Error(), "missing the global privileges") {
+		t.Errorf("providerConfigure with verify_privileges = %q returned %v", verifyPrivilegesError, err)
+	}
+
+	admin := testGrantsServer(t, "GRANT ALL PRIVILEGES ON *.* TO `tf`@`%` WITH GRANT OPTION")
+	if _, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint":          admin.addr(),
+		"verify_privileges": verifyPrivilegesError,
+	}); err != nil {
+		t.Errorf("providerConfigure for an admin returned %s", err)
+	}
+}
+
+func TestFlushPrivilegesAfter(t *testing.T) {
+	server := newFakeServer(t, func(query string) fakeResult {
+		if query == "SHOW GRANTS FOR 'app'@'%'" {
+			return fakeResult{
+				columns: []fakeColumn{{"Grants for app@%", fakeTypeVarString}},
+				rows:    [][]interface{}{{"GRANT `reader`@`%` TO `app`@`%`"}},
+			}
+		}
+		return fakeDefaultResult(query)
+	})
+	raw := map[string]interface{}{"role": "reader", "to_user": "app", "to_host": "%"}
+
+	for _, flush := range []bool{false, true} {
+		conf, err := testProviderConfigure(t, map[string]interface{}{
+			"endpoint":               server.addr(),
+			"flush_privileges_after": flush,
+		})
+		if err != nil {
+			t.Fatalf("providerConfigure returned %s", err)
+		}
+
+		before := len(server.receivedQueries())
+		d := schema.TestResourceDataRaw(t, ResourceRoleGrant().Schema, raw)
+		d.SetId("reader@%:app@%")
+		if err := DeleteRoleGrant(d, conf); err != nil {
+			t.Fatalf("DeleteRoleGrant returned %s", err)
+		}
+		queries := server.receivedQueries()[before:]
+		if flushed := fakeQueriesContain(queries, "FLUSH PRIVILEGES"); flushed != flush {
+			t.Errorf("DeleteRoleGrant with flush_privileges_after = %t ran %q", flush, queries)
+		}
+	}
+}
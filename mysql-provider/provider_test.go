@@ -0,0 +1,727 @@
+package mysql_provider
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+// testAccDatabasePrefix prefixes the names of the databases acceptance tests
+// create, so the sweeper can tell them apart from everything else.
+const testAccDatabasePrefix = "tf_acc_"
+
+var testAccProviders map[string]terraform.ResourceProvider
+var testAccProvider *schema.Provider
+
+func init() {
+	testAccProvider = Provider().(*schema.Provider)
+	testAccProviders = map[string]terraform.ResourceProvider{
+		"mysql": testAccProvider,
+	}
+
+	resource.AddTestSweepers("mysql_database", &resource.Sweeper{
+		Name: "mysql_database",
+		F:    testSweepDatabases,
+	})
+}
+
+// TestMain runs the sweepers instead of the tests when -sweep is given, e.g.
+// "go test ./mysql-provider -v -sweep=local".
+func TestMain(m *testing.M) {
+	resource.TestMain(m)
+}
+
+func TestProvider(t *testing.T) {
+	if err := Provider().(*schema.Provider).InternalValidate(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+// testAccPreCheck skips acceptance tests unless MYSQL_ENDPOINT points them at
+// a server. The credentials come from MYSQL_USERNAME and MYSQL_PASSWORD, like
+// they do for the provider itself.
+func testAccPreCheck(t *testing.T) {
+	if os.Getenv("MYSQL_ENDPOINT") == "" {
+		t.Skip("MYSQL_ENDPOINT must be set for acceptance tests")
+	}
+	if os.Getenv("MYSQL_USERNAME") == "" {
+		t.Fatal("MYSQL_USERNAME must be set for acceptance tests")
+	}
+}
+
+// testAccConfiguration configures the acceptance test provider from the
+// environment and returns its configuration.
+func testAccConfiguration(t *testing.T) *MySQLConfiguration {
+	if err := testAccProvider.Configure(terraform.NewResourceConfigRaw(nil)); err != nil {
+		t.Fatalf("Error configuring the provider: %s", err)
+	}
+	return testAccProvider.Meta().(*MySQLConfiguration)
+}
+
+// testAccSkipUnlessMySQL skips the test unless the server is MySQL minVersion
+// or newer, e.g. "5.7.0" or "8.0.0".
+func testAccSkipUnlessMySQL(t *testing.T, minVersion string) {
+	testAccSkipUnlessFlavor(t, false, minVersion)
+}
+
+// testAccSkipUnlessMariaDB skips the test unless the server is MariaDB
+// minVersion or newer, e.g. "10.2.0".
+func testAccSkipUnlessMariaDB(t *testing.T, minVersion string) {
+	testAccSkipUnlessFlavor(t, true, minVersion)
+}
+
+func testAccSkipUnlessFlavor(t *testing.T, mariaDB bool, minVersion string) {
+	versionString, err := testAccConfiguration(t).serverVersionString()
+	if err != nil {
+		t.Fatalf("Error reading the server version: %s", err)
+	}
+	if strings.Contains(versionString, "MariaDB") != mariaDB {
+		t.Skipf("Server %s is not the flavour this test targets", versionString)
+	}
+
+	requiredVersion := version.Must(version.NewVersion(minVersion))
+	currentVersion, err := parseServerVersion(versionString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if currentVersion.LessThan(requiredVersion) {
+		t.Skipf("Server %s is older than %s", versionString, minVersion)
+	}
+}
+
+// testSweepDatabases drops the databases left behind by failed acceptance
+// tests.
+func testSweepDatabases(region string) error {
+	if err := testAccProvider.Configure(terraform.NewResourceConfigRaw(nil)); err != nil {
+		return err
+	}
+	db := testAccProvider.Meta().(*MySQLConfiguration).connection()
+
+	rows, err := db.Query("SHOW DATABASES LIKE ?", likePatternReplacer.Replace(testAccDatabasePrefix)+"%")
+	if err != nil {
+		return fmt.Errorf("Error listing databases: %s", err)
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return err
+		}
+		names = append(names, name)
+	}
+	rows.Close()
+
+	for _, name := range names {
+		if _, err := db.Exec("DROP DATABASE " + quoteIdentifier(name)); err != nil {
+			return fmt.Errorf("Error dropping database %s: %s", name, err)
+		}
+	}
+	return nil
+}
+
+// testAccCheckRows checks that query returns want rows on the test server,
+// e.g. 0 in a CheckDestroy.
+func testAccCheckRows(want int, query string, args ...interface{}) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		db := testAccProvider.Meta().(*MySQLConfiguration).connection()
+		rows, err := db.Query(query, args...)
+		if err != nil {
+			return fmt.Errorf("Error running %q: %s", query, err)
+		}
+		defer rows.Close()
+
+		got := 0
+		for rows.Next() {
+			got++
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if got != want {
+			return fmt.Errorf("%q with %v returned %d rows, want %d", query, args, got, want)
+		}
+		return nil
+	}
+}
+
+func TestQuoteIdentifier(t *testing.T) {
+	cases := map[string]string{
+		"app":     "`app`",
+		"my`db":   "`my``db`",
+		"a b":     "`a b`",
+		"``":      "``````",
+		"app.tbl": "`app.tbl`",
+	}
+	for in, expected := range cases {
+		if got := quoteIdentifier(in); got != expected {
+			t.Errorf("quoteIdentifier(%q) = %q, want %q", in, got, expected)
+		}
+	}
+}
+
+func TestQuoteString(t *testing.T) {
+	cases := map[string]string{
+		"secret":  "'secret'",
+		"it's":    "'it''s'",
+		`back\sl`: `'back\\sl'`,
+		"a\nb":    `'a\nb'`,
+		"\x00":    `'\0'`,
+		"\x1a":    `'\Z'`,
+	}
+	for in, expected := range cases {
+		if got := quoteString(in); got != expected {
+			t.Errorf("quoteString(%q) = %q, want %q", in, got, expected)
+		}
+	}
+}
+
+func TestProviderConfigure(t *testing.T) {
+	server := newFakeServer(t, nil)
+
+	conf, err := testProviderConfigure(t, map[string]interface{}{"endpoint": server.addr()})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+	if err := conf.connection().Ping(); err != nil {
+		t.Errorf("Ping returned %s", err)
+	}
+	if !fakeQueriesContain(server.receivedQueries(), "@@SESSION.sql_mode") {
+		t.Errorf("providerConfigure didn't check the session sql_mode, got %q", server.receivedQueries())
+	}
+}
+
+func TestProviderConfigureConnParams(t *testing.T) {
+	server := newFakeServer(t, nil)
+
+	conf, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint":    server.addr(),
+		"conn_params": map[string]interface{}{"parseTime": "true", "loc": "Europe/Berlin"},
+	})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+	dsn := conf.Config.FormatDSN()
+	for _, param := range []string{"parseTime=true", "loc=Europe%2FBerlin"} {
+		if !strings.Contains(dsn, param) {
+			t.Errorf("DSN %q is missing %s", dsn, param)
+		}
+	}
+
+	_, err = testProviderConfigure(t, map[string]interface{}{
+		"endpoint":    server.addr(),
+		"conn_params": map[string]interface{}{"a&b": "c"},
+	})
+	if err == nil {
+		t.Error("providerConfigure accepted a conn_params key that needs escaping")
+	}
+}
+
+func TestNormalizeEndpoint(t *testing.T) {
+	cases := map[string]string{
+		"localhost:3306":         "localhost:3306",
+		"mysql://localhost:3306": "localhost:3306",
+		"TCP://db:3306":          "db:3306",
+		"mysql://":               "mysql://",
+	}
+	for in, expected := range cases {
+		if got := normalizeEndpoint(in); got != expected {
+			t.Errorf("normalizeEndpoint(%q) = %q, want %q", in, got, expected)
+		}
+	}
+}
+
+func TestProviderConfigureSchemedEndpoint(t *testing.T) {
+	server := newFakeServer(t, nil)
+	output := testCaptureLog(t)
+
+	conf, err := testProviderConfigure(t, map[string]interface{}{"endpoint": "mysql://" + server.addr()})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+	if conf.Config.Addr != server.addr() {
+		t.Errorf("providerConfigure connected to %q, want %q", conf.Config.Addr, server.addr())
+	}
+	if !strings.Contains(output.String(), "Endpoints take no scheme") {
+		t.Errorf("providerConfigure didn't warn about the scheme, logged %q", output.String())
+	}
+}
+
+func TestProviderConfigureEmptyEndpoint(t *testing.T) {
+	testSetenv(t, "MYSQL_ENDPOINT", "")
+
+	for _, raw := range []map[string]interface{}{{}, {"endpoint": ""}} {
+		_, err := testProviderConfigure(t, raw)
+		if err == nil || !strings.Contains(err.Error(), "must be set to a non-empty value") {
+			t.Errorf("providerConfigure(%v) returned %v, want a configuration error", raw, err)
+		}
+	}
+}
+
+func TestValidateEndpoint(t *testing.T) {
+	valid := []string{
+		"localhost:3306",
+		"10.0.0.1:3306",
+		"[::1]:3306",
+		"[fe80::1%eth0]:3306",
+		"/var/run/mysqld/mysqld.sock",
+	}
+	for _, endpoint := range valid {
+		if _, errs := validateEndpoint(endpoint, "endpoint"); len(errs) != 0 {
+			t.Errorf("validateEndpoint(%q) returned %v, want no errors", endpoint, errs)
+		}
+	}
+
+	invalid := map[string]string{
+		"":               "must not be an empty string",
+		"localhost":      "host:port pair",
+		":3306":          "missing a host",
+		"localhost:0":    "invalid port",
+		"localhost:3x06": "invalid port",
+		"::1":            "IPv6 addresses in brackets",
+		"[::1]":          "IPv6 addresses in brackets",
+		"fe80::1:3306":   "IPv6 addresses in brackets",
+	}
+	for endpoint, expected := range invalid {
+		_, errs := validateEndpoint(endpoint, "endpoint")
+		if len(errs) == 0 {
+			t.Errorf("validateEndpoint(%q) returned no errors", endpoint)
+			continue
+		}
+		if !strings.Contains(errs[0].Error(), expected) {
+			t.Errorf("validateEndpoint(%q) returned %q, want it to mention %q", endpoint, errs[0], expected)
+		}
+	}
+}
+
+func TestProviderConfigureIPv6(t *testing.T) {
+	listener, err := net.Listen("tcp", "[::1]:0")
+	if err != nil {
+		t.Skipf("IPv6 loopback is unavailable: %s", err)
+	}
+	listener.Close()
+	server := newFakeServerListening(t, "tcp", "[::1]:0", nil)
+
+	conf, err := testProviderConfigure(t, map[string]interface{}{"endpoint": server.addr()})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+	if conf.Config.Net != "tcp" || conf.Config.Addr != server.addr() {
+		t.Errorf("providerConfigure connected over %s to %s, want tcp to %s", conf.Config.Net, conf.Config.Addr, server.addr())
+	}
+	if dsn := conf.Config.FormatDSN(); !strings.Contains(dsn, "@tcp("+server.addr()+")/") {
+		t.Errorf("DSN %q doesn't address %s", dsn, server.addr())
+	}
+}
+
+func TestProviderConfigureIdleConns(t *testing.T) {
+	server := newFakeServer(t, nil)
+
+	conf, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint":               server.addr(),
+		"max_idle_conns":         1,
+		"conn_max_idle_time_sec": 30,
+	})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+	if conf.ConnMaxIdleTime != 30*time.Second {
+		t.Errorf("ConnMaxIdleTime = %s, want 30s", conf.ConnMaxIdleTime)
+	}
+
+	// Of three connections released at once only one is kept idle.
+	db := conf.connection()
+	var conns []*sql.Conn
+	for i := 0; i < 3; i++ {
+		conn, err := db.Conn(context.Background())
+		if err != nil {
+			t.Fatalf("Conn returned %s", err)
+		}
+		conns = append(conns, conn)
+	}
+	for _, conn := range conns {
+		conn.Close()
+	}
+	if stats := db.Stats(); stats.Idle != 1 {
+		t.Errorf("%d idle connections, want 1", stats.Idle)
+	}
+}
+
+func TestProviderConfigurePassword(t *testing.T) {
+	// Otherwise the password of the acceptance tests is picked up.
+	testSetenv(t, "MYSQL_PASSWORD", "")
+	server := newFakeServer(t, nil)
+
+	if _, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint":              server.addr(),
+		"password":              "",
+		"authentication_plugin": socketAuth,
+	}); err != nil {
+		t.Errorf("providerConfigure without a password for %s returned %s", socketAuth, err)
+	}
+
+	_, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint": server.addr(),
+		"password": "",
+	})
+	if err == nil || !strings.Contains(err.Error(), "password or password_file must be set") {
+		t.Errorf("providerConfigure without a password returned %v", err)
+	}
+}
+
+func TestProviderConfigureAccessDeniedFailsFast(t *testing.T) {
+	server := newFakeServer(t, nil)
+	server.rejectLogins(&mysql.MySQLError{Number: 1045, Message: "Access denied for user 'tf'@'localhost' (using password: YES)"})
+
+	start := time.Now()
+	_, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint":                  server.addr(),
+		"connect_retry_timeout_sec": 30,
+	})
+	if err == nil || !strings.Contains(err.Error(), "Error 1045") {
+		t.Errorf("providerConfigure returned %v, want an access denied", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("providerConfigure gave up after %s, want it to fail fast", elapsed)
+	}
+	if attempts := server.loginAttempts(); attempts != 1 {
+		t.Errorf("providerConfigure logged in %d times, want 1", attempts)
+	}
+}
+
+func TestProviderConfigureWithoutRetries(t *testing.T) {
+	server := newFakeServer(t, nil)
+	server.rejectLogins(&mysql.MySQLError{Number: 1040, Message: "Too many connections"})
+
+	_, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint":                  server.addr(),
+		"connect_retry_timeout_sec": 0,
+	})
+	if err == nil || !strings.Contains(err.Error(), "Error 1040") {
+		t.Errorf("providerConfigure returned %v, want too many connections", err)
+	}
+	if attempts := server.loginAttempts(); attempts != 1 {
+		t.Errorf("providerConfigure without retries logged in %d times, want 1", attempts)
+	}
+}
+
+func TestIsTerminalConnectError(t *testing.T) {
+	cases := map[error]bool{
+		&mysql.MySQLError{Number: 1045}:                                 true,
+		&mysql.MySQLError{Number: 1049}:                                 true,
+		&mysql.MySQLError{Number: 2006}:                                 false,
+		&net.DNSError{Err: "no such host", IsNotFound: true}:            true,
+		&net.DNSError{Err: "timeout", IsTimeout: true}:                  false,
+		&net.OpError{Op: "dial", Err: errors.New("connection refused")}: false,
+	}
+	for err, expected := range cases {
+		if got := isTerminalConnectError(err); got != expected {
+			t.Errorf("isTerminalConnectError(%#v) = %t, want %t", err, got, expected)
+		}
+	}
+}
+
+func TestProviderConfigureTimeouts(t *testing.T) {
+	server := newFakeServer(t, nil)
+
+	conf, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint":          server.addr(),
+		"conn_timeout_sec":  5,
+		"read_timeout_sec":  30,
+		"write_timeout_sec": 15,
+	})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+	dsn := conf.Config.FormatDSN()
+	for _, param := range []string{"timeout=5s", "readTimeout=30s", "writeTimeout=15s"} {
+		if !strings.Contains(dsn, param) {
+			t.Errorf("DSN %q is missing %s", dsn, param)
+		}
+	}
+
+	conf, err = testProviderConfigure(t, map[string]interface{}{"endpoint": server.addr()})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+	if dsn := conf.Config.FormatDSN(); strings.Contains(dsn, "readTimeout") {
+		t.Errorf("DSN %q has a readTimeout without read_timeout_sec", dsn)
+	}
+}
+
+func TestProviderConfigureEndpointFailover(t *testing.T) {
+	testSetenv(t, "MYSQL_ENDPOINT", "")
+	server := newFakeServer(t, nil)
+
+	// Nothing listens on a port that was just closed.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dead := listener.Addr().String()
+	listener.Close()
+
+	conf, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoints": []interface{}{dead, server.addr()},
+	})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+	if conf.Config.Addr != server.addr() {
+		t.Errorf("providerConfigure connected to %s, want the live endpoint %s", conf.Config.Addr, server.addr())
+	}
+	if err := conf.connection().Ping(); err != nil {
+		t.Errorf("Ping returned %s", err)
+	}
+
+	_, err = testProviderConfigure(t, map[string]interface{}{
+		"endpoints": []interface{}{dead},
+	})
+	if err == nil {
+		t.Error("providerConfigure with only a dead endpoint returned no error")
+	}
+}
+
+func TestEndpointProtocol(t *testing.T) {
+	cases := []struct {
+		endpoint string
+		protocol string
+		expected string
+	}{
+		{"localhost:3306", "", "tcp"},
+		{"/tmp/mysql.sock", "", "unix"},
+		{"/tmp/mysql.sock", "tcp", "tcp"},
+		{"mysql.sock", "unix", "unix"},
+		{"project:region:instance", "cloudsql", "cloudsql"},
+	}
+	for _, c := range cases {
+		if got := endpointProtocol(c.endpoint, c.protocol); got != c.expected {
+			t.Errorf("endpointProtocol(%q, %q) = %q, want %q", c.endpoint, c.protocol, got, c.expected)
+		}
+	}
+}
+
+func TestProviderConfigureUnixProtocol(t *testing.T) {
+	// A socket path relative to the working directory doesn't start with a
+	// slash.
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(dir) })
+	server := newFakeServerListening(t, "unix", "mysql.sock", nil)
+
+	conf, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint": "mysql.sock",
+		"protocol": "unix",
+	})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+	if conf.Config.Net != "unix" {
+		t.Errorf("Net = %q, want unix", conf.Config.Net)
+	}
+	if server.loginAttempts() == 0 {
+		t.Error("providerConfigure didn't connect through the unix socket")
+	}
+}
+
+// testCaptureLog collects what is logged until the end of the test.
+func testCaptureLog(t *testing.T) *bytes.Buffer {
+	var output bytes.Buffer
+	previous := log.Writer()
+	log.SetOutput(&output)
+	t.Cleanup(func() { log.SetOutput(previous) })
+	return &output
+}
+
+func TestConnectionRecovers(t *testing.T) {
+	server := newFakeServer(t, nil)
+	conf, err := testProviderConfigure(t, map[string]interface{}{"endpoint": server.addr()})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+
+	// Pooled connections the server dropped are replaced.
+	server.dropConnections()
+	if _, err := conf.connection().Exec("DO 1"); err != nil {
+		t.Errorf("Exec after the server dropped the connections returned %s", err)
+	}
+
+	// A handle that no longer works at all is replaced by a new one.
+	previous := conf.Db
+	previous.Close()
+	db := conf.connection()
+	if db == previous {
+		t.Fatal("connection returned the closed handle")
+	}
+	if _, err := db.Exec("DO 1"); err != nil {
+		t.Errorf("Exec on the new handle returned %s", err)
+	}
+}
+
+func TestProviderConfigureStatementParams(t *testing.T) {
+	server := newFakeServer(t, nil)
+
+	conf, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint":           server.addr(),
+		"multi_statements":   true,
+		"interpolate_params": true,
+	})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+	dsn := conf.Config.FormatDSN()
+	for _, param := range []string{"multiStatements=true", "interpolateParams=true"} {
+		if !strings.Contains(dsn, param) {
+			t.Errorf("DSN %q is missing %s", dsn, param)
+		}
+	}
+
+	conf, err = testProviderConfigure(t, map[string]interface{}{"endpoint": server.addr()})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+	if dsn := conf.Config.FormatDSN(); strings.Contains(dsn, "multiStatements") || strings.Contains(dsn, "interpolateParams") {
+		t.Errorf("DSN %q enables multiStatements or interpolateParams by default", dsn)
+	}
+}
+
+func TestProviderConfigurePasswordFile(t *testing.T) {
+	testSetenv(t, "MYSQL_PASSWORD", "")
+	server := newFakeServer(t, nil)
+
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	if err := ioutil.WriteFile(passwordFile, []byte("s3cr3t\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	conf, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint":      server.addr(),
+		"password":      "",
+		"password_file": passwordFile,
+	})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+	if conf.Config.Passwd != "s3cr3t" {
+		t.Errorf("password read from password_file = %q, want s3cr3t", conf.Config.Passwd)
+	}
+
+	_, err = testProviderConfigure(t, map[string]interface{}{
+		"endpoint":      server.addr(),
+		"password_file": filepath.Join(t.TempDir(), "missing"),
+	})
+	if err == nil || !strings.Contains(err.Error(), "Could not read password_file") {
+		t.Errorf("providerConfigure with a missing password_file returned %v", err)
+	}
+}
+
+func TestProviderConfigureCleartextWithoutTLS(t *testing.T) {
+	server := newFakeServer(t, nil)
+
+	_, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint":              server.addr(),
+		"tls":                   "false",
+		"authentication_plugin": cleartextPasswords,
+	})
+	if err == nil || !strings.Contains(err.Error(), "allow_cleartext_without_tls") {
+		t.Errorf("providerConfigure with cleartext passwords and without TLS returned %v", err)
+	}
+
+	conf, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint":                    server.addr(),
+		"tls":                         "false",
+		"authentication_plugin":       cleartextPasswords,
+		"allow_cleartext_without_tls": true,
+	})
+	if err != nil {
+		t.Fatalf("providerConfigure with allow_cleartext_without_tls returned %s", err)
+	}
+	if !conf.Config.AllowCleartextPasswords {
+		t.Error("AllowCleartextPasswords isn't set for the cleartext plugin")
+	}
+}
+
+func TestProviderConfigureSessionVariables(t *testing.T) {
+	server := newFakeServer(t, nil)
+	conf, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint": server.addr(),
+		"session_variables": map[string]interface{}{
+			"sql_mode":  "TRADITIONAL",
+			"time_zone": "+00:00",
+		},
+	})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+
+	// A fresh connection sets them again.
+	server.dropConnections()
+	before := len(server.receivedQueries())
+	if _, err := conf.connection().Exec("DO 1"); err != nil {
+		t.Fatalf("Exec returned %s", err)
+	}
+	queries := server.receivedQueries()[before:]
+	for _, stmtSQL := range []string{"sql_mode='TRADITIONAL'", "time_zone='+00:00'"} {
+		if !fakeQueriesContain(queries, stmtSQL) {
+			t.Errorf("the new connection ran %q, want %s", queries, stmtSQL)
+		}
+	}
+
+	_, err = testProviderConfigure(t, map[string]interface{}{
+		"endpoint":          server.addr(),
+		"session_variables": map[string]interface{}{"time_zone = '+00:00'; DROP DATABASE app; SET x": "1"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "Invalid session_variables name") {
+		t.Errorf("providerConfigure with an invalid variable name returned %v", err)
+	}
+}
+
+func TestProviderConfigureDatabase(t *testing.T) {
+	server := newFakeServer(t, nil)
+
+	conf, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint": server.addr(),
+		"database": "app",
+	})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+	if conf.Config.DBName != "app" {
+		t.Errorf("DBName = %q, want app", conf.Config.DBName)
+	}
+
+	// A missing schema fails the connection right away.
+	server.rejectLogins(&mysql.MySQLError{Number: 1049, Message: "Unknown database 'gone'"})
+	start := time.Now()
+	_, err = testProviderConfigure(t, map[string]interface{}{
+		"endpoint":                  server.addr(),
+		"database":                  "gone",
+		"connect_retry_timeout_sec": 30,
+	})
+	if err == nil || !strings.Contains(err.Error(), "Unknown database") {
+		t.Errorf("providerConfigure with a missing database returned %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("providerConfigure retried a missing database for %s", elapsed)
+	}
+}
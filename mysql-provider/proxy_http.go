@@ -0,0 +1,77 @@
+package mysql_provider
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/proxy"
+)
+
+// httpConnectDialer tunnels connections through an HTTP proxy with CONNECT
+// requests, for networks that don't offer a SOCKS proxy. An https proxy URL
+// talks TLS to the proxy itself.
+type httpConnectDialer struct {
+	proxyURL *url.URL
+	forward  proxy.Dialer
+}
+
+func newHTTPConnectDialer(proxyURL *url.URL, forward proxy.Dialer) *httpConnectDialer {
+	return &httpConnectDialer{proxyURL: proxyURL, forward: forward}
+}
+
+func (h *httpConnectDialer) Dial(network string, addr string) (net.Conn, error) {
+	conn, err := h.forward.Dial("tcp", h.proxyURL.Host)
+	if err != nil {
+		return nil, err
+	}
+	if h.proxyURL.Scheme == "https" {
+		conn = tls.Client(conn, &tls.Config{ServerName: h.proxyURL.Hostname()})
+	}
+
+	req := &http.Request{
+		Method: "CONNECT",
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: http.Header{},
+	}
+	if h.proxyURL.User != nil {
+		password, _ := h.proxyURL.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(h.proxyURL.User.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("Could not send CONNECT to proxy %s: %s", h.proxyURL.Host, err)
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("Could not read CONNECT response of proxy %s: %s", h.proxyURL.Host, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("Proxy %s refused to connect to %s: %s", h.proxyURL.Host, addr, resp.Status)
+	}
+
+	// MySQL servers speak first, so the greeting may already sit in the
+	// reader's buffer.
+	return &bufferedConn{Conn: conn, reader: reader}, nil
+}
+
+// bufferedConn reads through a bufio.Reader wrapping the connection.
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (b *bufferedConn) Read(p []byte) (int, error) {
+	return b.reader.Read(p)
+}
@@ -1,19 +1,29 @@
 package mysql_provider
 
 import (
+	"context"
 	"database/sql"
 	"github.com/go-sql-driver/mysql"
-	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 	"fmt"
 	"log"
+	"regexp"
 	"strings"
+	"time"
 )
 
 
 const defCharSetKey = "CHARACTER SET "
 const defaultCollateKey = "COLLATE "
+const defaultEncryptionKey = "ENCRYPTION="
 const unknownDatabaseErr = 1049
+const dropUnknownDatabaseErr = 1008
+const databaseExistsErr = 1007
+const unknownCollationErr = 1273
+const databaseAccessDeniedErr = 1044
+const commentMinMariaDBVersion = "10.5.0"
 
 func ResourceDB() *schema.Resource {
 	return &schema.Resource{
@@ -23,70 +33,327 @@ func ResourceDB() *schema.Resource {
 				Required: true,
 				ForceNew: true,
 			},
+			// When omitted these inherit the provider's default_charset and
+			// default_collation.
 			"default_charset" : {
 				Type: schema.TypeString,
 				Optional: true,
-				Default: "utf8",
+				Computed: true,
+				DiffSuppressFunc: suppressCharsetDiff,
 			},
 			"default_collation": {
 				Type: schema.TypeString,
 				Optional: true,
-				Default: "utf8_general_ci",
+				Computed: true,
+				DiffSuppressFunc: suppressCharsetDiff,
+			},
+			"default_encryption": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{"Y", "N"}, false),
+			},
+			// Only MariaDB 10.5 and newer support database comments.
+			"comment": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			// Adopts an existing database instead of failing. Its charset and
+			// collation are left as they are, differences show up in the next
+			// plan.
+			"create_if_not_exists": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"recreate_on_charset_change": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			// Lets the resource create, adopt or drop the schemas the server
+			// maintains itself, e.g. mysql or information_schema.
+			"allow_system_schema": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			// Plans a replacement instead of an ALTER DATABASE when the
+			// charset or collation changes.
+			"charset_change_forces_new": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
 			},
 		},
 		SchemaVersion:      0,
 		MigrateState:       nil,
 		StateUpgraders:     nil,
 		Create:             CreateDb,
-		Read:               nil,
+		Read:               ReadDb,
 		Update:             UpdateDb,
-		Delete:             nil,
-		Exists:             nil,
-		CustomizeDiff:      nil,
-		Importer:           nil,
+		Delete:             DeleteDb,
+		Exists:             ExistsDb,
+		CustomizeDiff:      customdiff.All(
+			validateManagedSchema,
+			validateCharsetCollation,
+			customdiff.ForceNewIf("default_charset", charsetChangeForcesNew("default_charset")),
+			customdiff.ForceNewIf("default_collation", charsetChangeForcesNew("default_collation")),
+		),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
 		DeprecationMessage: "",
-		Timeouts:           nil,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
 		Description:        "",
 	}
 }
 
 func CreateDb(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*MySQLConfiguration).Db
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).operationContext(d, schema.TimeoutCreate)
+	defer cancel()
+
+	if err := checkSystemSchema(d, d.Get("name").(string), "create"); err != nil {
+		return err
+	}
+	if err := meta.(*MySQLConfiguration).checkManagedSchema(d.Get("name").(string)); err != nil {
+		return err
+	}
+
+	if d.Get("default_encryption").(string) != "" {
+		if err := requireMySQLVersion(meta.(*MySQLConfiguration), "default_encryption", "8.0.16"); err != nil {
+			return err
+		}
+	}
+
+	if d.Get("comment").(string) != "" {
+		if err := requireMariaDBVersion(meta.(*MySQLConfiguration), "comment", commentMinMariaDBVersion); err != nil {
+			return err
+		}
+	}
+
+	applyProviderCharsetDefaults(d, meta.(*MySQLConfiguration))
+
 	sqlStatment := databaseSQLCMD("CREATE", d)
-	log.Println("Executing statement:", sqlStatment)
-	_, err := db.Exec(sqlStatment)
+	logStatement(sqlStatment)
+	err := execDDL(ctx, db, sqlStatment)
 	if err != nil {
-		return err
+		if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == databaseExistsErr {
+			name := d.Get("name").(string)
+			return fmt.Errorf("Database %s already exists. Bring it under management with \"terraform import mysql_database.<name> %s\", or set create_if_not_exists to adopt it", name, name)
+		}
+		return unknownCollationError(d, err)
 	}
 	d.SetId(d.Get("name").(string))
 
+	// The database exists at this point. Failing the create would taint it
+	// and have the next apply replace it, so a failed read is left to the
+	// next refresh instead.
+	if err := ReadDb(d, meta); err != nil {
+		log.Printf("[WARN] Created database %s but could not read it back, it is refreshed on the next plan: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+func UpdateDb(d *schema.ResourceData, meta interface{}) error {
+	if !d.HasChange("default_charset") && !d.HasChange("default_collation") && !d.HasChange("default_encryption") && !d.HasChange("comment") {
+		return ReadDb(d, meta)
+	}
+
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).operationContext(d, schema.TimeoutUpdate)
+	defer cancel()
+
+	if d.HasChange("default_encryption") {
+		if err := requireMySQLVersion(meta.(*MySQLConfiguration), "default_encryption", "8.0.16"); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("comment") {
+		if err := requireMariaDBVersion(meta.(*MySQLConfiguration), "comment", commentMinMariaDBVersion); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("default_charset") && d.Get("recreate_on_charset_change").(bool) {
+		return recreateDb(d, meta)
+	}
+
+	sqlStatment := alterDatabaseSQLCMD(d)
+	if sqlStatment == "" {
+		return ReadDb(d, meta)
+	}
+	logStatement(sqlStatment)
+	err := execDDL(ctx, db, sqlStatment)
+	if err != nil {
+		return unknownCollationError(d, err)
+	}
+
+	return ReadDb(d, meta)
+}
+
+// unknownCollationError points at SHOW COLLATION when the server rejected
+// default_collation, err is returned as is otherwise.
+func unknownCollationError(d *schema.ResourceData, err error) error {
+	if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == unknownCollationErr {
+		return fmt.Errorf("Unknown collation %q for database %s, list the collations the server supports with \"SHOW COLLATION\", or those of a charset with \"SHOW COLLATION WHERE Charset = '<charset>'\"", d.Get("default_collation").(string), d.Get("name").(string))
+	}
+	return err
+}
+
+// applyProviderCharsetDefaults fills in the charset and collation the resource
+// omitted from the provider defaults. The provider collation is only inherited
+// along with the provider charset, a resource picking its own charset gets
+// that charset's default collation from the server instead. A resource only
+// setting a collation gets neither, the collation implies its charset.
+func applyProviderCharsetDefaults(d *schema.ResourceData, conf *MySQLConfiguration) {
+	_, charsetSet := d.GetOkExists("default_charset")
+	_, collationSet := d.GetOkExists("default_collation")
+
+	if !charsetSet && !collationSet {
+		d.Set("default_charset", conf.DefaultCharset)
+	}
+	if !collationSet && d.Get("default_charset").(string) == conf.DefaultCharset {
+		d.Set("default_collation", conf.DefaultCollation)
+	}
+}
+
+// execDDL runs the statement and logs any warnings it raised, such as
+// deprecated charsets or collations, which MySQL otherwise only keeps around
+// for the connection that ran it.
+func execDDL(ctx context.Context, db *sql.DB, stmtSQL string) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = execContext(ctx, conn, stmtSQL)
+	if err != nil {
+		return err
+	}
+
+	rows, err := conn.QueryContext(ctx, "SHOW WARNINGS")
+	if err != nil {
+		log.Printf("[WARN] Could not read warnings of %q: %s", stmtSQL, err)
+		return nil
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var level, message string
+		var code int
+		if err := rows.Scan(&level, &code, &message); err != nil {
+			log.Printf("[WARN] Could not read warnings of %q: %s", stmtSQL, err)
+			return nil
+		}
+		log.Printf("[WARN] MySQL %s %d: %s", level, code, message)
+	}
+
+	return nil
+}
+
+// recreateDb drops and creates the database again, for servers that refuse
+// an in-place charset change. Everything stored in the database is lost.
+func recreateDb(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).operationContext(d, schema.TimeoutUpdate)
+	defer cancel()
+
+	name := d.Get("name").(string)
+	if err := checkSystemSchema(d, name, "recreate"); err != nil {
+		return err
+	}
+	log.Printf("[WARN] recreate_on_charset_change is set, dropping database %s and ALL of its contents to change its charset", name)
+
+	stmts := []string{
+		"DROP DATABASE " + quoteIdentifier(name),
+		databaseSQLCMD("CREATE", d),
+	}
+	for _, sqlStatment := range stmts {
+		logStatement(sqlStatment)
+		_, err := execContext(ctx, db, sqlStatment)
+		if err != nil {
+			return fmt.Errorf("Error recreating database %s: %s", name, err)
+		}
+	}
+
 	return ReadDb(d, meta)
 }
 
 func ReadDb(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*MySQLConfiguration).Db
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
 
+	name := d.Id()
+	options, err := readDatabaseOptions(ctx, meta.(*MySQLConfiguration), db, name)
+	if err != nil {
+		if mysqlErr, ok := err.(*mysql.MySQLError); ok {
+			if mysqlErr.Number == unknownDatabaseErr {
+				d.SetId("")
+				return nil
+			}
+			return fmt.Errorf("Error reading database %s: %s", name, err)
+		}
+		return err
+	}
+
+	d.Set("name", name)
+	d.Set("default_charset", options.Charset)
+	d.Set("default_collation", options.Collation)
+	d.Set("default_encryption", options.Encryption)
+
+	comment, err := readDatabaseComment(ctx, meta.(*MySQLConfiguration), db, name)
+	if err != nil {
+		return err
+	}
+	d.Set("comment", comment)
+
+	return nil
+}
+
+// databaseOptions holds the defaults parsed out of SHOW CREATE DATABASE.
+type databaseOptions struct {
+	Charset    string
+	Collation  string
+	Encryption string
+}
+
+// readDatabaseOptions returns the default charset, collation and encryption
+// of the named database. Errors from SHOW CREATE DATABASE are returned as is
+// so callers can tell an unknown database apart. When the connected account may
+// not run SHOW CREATE DATABASE they are read from information_schema instead.
+func readDatabaseOptions(ctx context.Context, conf *MySQLConfiguration, db *sql.DB, name string) (*databaseOptions, error) {
 	// This is kinda flimsy-feeling, since it depends on the formatting
 	// of the SHOW CREATE DATABASE output... but this data doesn't seem
 	// to be available any other way, so hopefully MySQL keeps this
 	// compatible in future releases.
 
-	name := d.Id()
 	stmtSQL := "SHOW CREATE DATABASE " + quoteIdentifier(name)
 
-	log.Println("Executing query:", stmtSQL)
+	logQuery(stmtSQL)
 	var createSQL, _database string
-	err := db.QueryRow(stmtSQL).Scan(&_database, &createSQL)
+	err := db.QueryRowContext(ctx, stmtSQL).Scan(&_database, &createSQL)
 	if err != nil {
 		if mysqlErr, ok := err.(*mysql.MySQLError); ok {
-			if mysqlErr.Number == unknownDatabaseErr {
-				d.SetId("")
-				return nil
+			if mysqlErr.Number == databaseAccessDeniedErr {
+				log.Printf("[WARN] Could not show create database %s, reading it from information_schema: %s", name, err)
+				return readSchemataOptions(ctx, conf, db, name)
 			}
+			return nil, err
 		}
-		return fmt.Errorf("Error during show create database: %s", err)
+		return nil, fmt.Errorf("Error during show create database: %s", err)
 	}
 
+	createSQL = normalizeCreateDatabaseSQL(createSQL)
 	defaultCharset := extractIdentAfter(createSQL, defCharSetKey)
 	defaultCollation := extractIdentAfter(createSQL, defaultCollateKey)
 
@@ -94,51 +361,316 @@ func ReadDb(d *schema.ResourceData, meta interface{}) error {
 		// MySQL doesn't return the collation if it's the default one for
 		// the charset, so if we don't have a collation we need to go
 		// hunt for the default.
-		stmtSQL := "SHOW COLLATION WHERE `Charset` = ? AND `Default` = 'Yes'"
-		var empty interface{}
-
-		requiredVersion, _ := version.NewVersion("8.0.0")
-		currentVersion, err := mySQLServerVersion(db)
+		var err error
+		defaultCollation, err = conf.defaultCollationForCharset(ctx, db, defaultCharset)
 		if err != nil {
-			return err
+			return nil, err
 		}
+	}
 
-		serverVersionString, err := mySQLServerVersionString(db)
-		if err != nil {
-			return err
+	return &databaseOptions{
+		Charset:    defaultCharset,
+		Collation:  defaultCollation,
+		Encryption: strings.Trim(extractIdentAfter(createSQL, defaultEncryptionKey), "'"),
+	}, nil
+}
+
+// readSchemataOptions is the information_schema fallback of
+// readDatabaseOptions. The encryption default is only there on MySQL 8.0.16
+// and newer, it is left empty otherwise.
+func readSchemataOptions(ctx context.Context, conf *MySQLConfiguration, db *sql.DB, name string) (*databaseOptions, error) {
+	encryptionColumn := "''"
+	if requireMySQLVersion(conf, "default_encryption", "8.0.16") == nil {
+		encryptionColumn = "`DEFAULT_ENCRYPTION`"
+	}
+
+	stmtSQL := "SELECT `DEFAULT_CHARACTER_SET_NAME`, `DEFAULT_COLLATION_NAME`, " + encryptionColumn + " FROM `information_schema`.`SCHEMATA` WHERE `SCHEMA_NAME` = ?"
+	logQuery(stmtSQL)
+
+	var options databaseOptions
+	err := db.QueryRowContext(ctx, stmtSQL, name).Scan(&options.Charset, &options.Collation, &options.Encryption)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// information_schema hides databases the account has no
+			// privileges on, so a missing row doesn't mean the database is
+			// gone.
+			return nil, fmt.Errorf("Database %s is not visible to the configured user, it needs a privilege on it to be read", name)
 		}
+		return nil, fmt.Errorf("Error reading database %s from information_schema: %s", name, err)
+	}
+
+	return &options, nil
+}
 
-		// MySQL 8 returns more data in a row.
-		var res error
-		if !strings.Contains(serverVersionString, "MariaDB") && currentVersion.GreaterThan(requiredVersion) {
-			res = db.QueryRow(stmtSQL, defaultCharset).Scan(&defaultCollation, &empty, &empty, &empty, &empty, &empty, &empty)
-		} else {
-			res = db.QueryRow(stmtSQL, defaultCharset).Scan(&defaultCollation, &empty, &empty, &empty, &empty, &empty)
+// readDatabaseComment returns the comment of the named database, always empty
+// on servers without database comments.
+func readDatabaseComment(ctx context.Context, conf *MySQLConfiguration, db *sql.DB, name string) (string, error) {
+	supported, err := isMariaDBVersion(conf, commentMinMariaDBVersion)
+	if err != nil || !supported {
+		return "", err
+	}
+
+	stmtSQL := "SELECT `SCHEMA_COMMENT` FROM `information_schema`.`SCHEMATA` WHERE `SCHEMA_NAME` = ?"
+	logQuery(stmtSQL)
+
+	var comment string
+	err = db.QueryRowContext(ctx, stmtSQL, name).Scan(&comment)
+	if err != nil && err != sql.ErrNoRows {
+		return "", fmt.Errorf("Error reading comment of database %s: %s", name, err)
+	}
+
+	return comment, nil
+}
+
+func DeleteDb(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).operationContext(d, schema.TimeoutDelete)
+	defer cancel()
+
+	name := d.Id()
+	if err := checkSystemSchema(d, name, "drop"); err != nil {
+		return err
+	}
+	if err := meta.(*MySQLConfiguration).checkManagedSchema(name); err != nil {
+		return err
+	}
+
+	stmtSQL := "DROP DATABASE IF EXISTS " + quoteIdentifier(name)
+	logStatement(stmtSQL)
+
+	_, err := execContext(ctx, db, stmtSQL)
+	if err != nil {
+		// The database was already dropped out-of-band, nothing left to do.
+		if mysqlErr, ok := err.(*mysql.MySQLError); !ok || mysqlErr.Number != dropUnknownDatabaseErr {
+			return fmt.Errorf("Error dropping database %s: %s", name, err)
 		}
+	}
 
-		if res != nil {
-			if res == sql.ErrNoRows {
-				return fmt.Errorf("Charset %s has no default collation", defaultCharset)
+	d.SetId("")
+	return nil
+}
+
+// attributeGetter is implemented by both *schema.ResourceData and
+// *schema.ResourceDiff.
+type attributeGetter interface {
+	Get(key string) interface{}
+}
+
+// checkSystemSchema refuses to operate on systemDatabases unless
+// allow_system_schema is set.
+func checkSystemSchema(d attributeGetter, name string, operation string) error {
+	if systemDatabases[strings.ToLower(name)] && !d.Get("allow_system_schema").(bool) {
+		return fmt.Errorf("Refusing to %s system database %s, set allow_system_schema to manage it anyway", operation, name)
+	}
+	return nil
+}
+
+func ExistsDb(d *schema.ResourceData, meta interface{}) (bool, error) {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	stmtSQL := "SHOW DATABASES LIKE ?"
+	logQuery(stmtSQL)
+
+	var _database string
+	err := db.QueryRowContext(ctx, stmtSQL, likePatternReplacer.Replace(d.Id())).Scan(&_database)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		if mysqlErr, ok := err.(*mysql.MySQLError); ok {
+			if mysqlErr.Number == unknownDatabaseErr {
+				return false, nil
 			}
+		}
+		return false, fmt.Errorf("Error checking database existence: %s", err)
+	}
+
+	return true, nil
+}
+
+// defaultCollationForCharset caches the lookups of
+// queryDefaultCollationForCharset. The lock isn't held during the query, so
+// concurrent misses may look the same charset up more than once.
+func (c *MySQLConfiguration) defaultCollationForCharset(ctx context.Context, db *sql.DB, defaultCharset string) (string, error) {
+	c.cacheLock.Lock()
+	collation, ok := c.defaultCollations[defaultCharset]
+	c.cacheLock.Unlock()
+	if ok {
+		return collation, nil
+	}
+
+	collation, err := queryDefaultCollationForCharset(ctx, db, defaultCharset)
+	if err != nil {
+		return "", err
+	}
+
+	c.cacheLock.Lock()
+	defer c.cacheLock.Unlock()
+	if c.defaultCollations == nil {
+		c.defaultCollations = map[string]string{}
+	}
+	c.defaultCollations[defaultCharset] = collation
+
+	return collation, nil
+}
+
+// queryDefaultCollationForCharset runs the SHOW COLLATION lookup and picks the
+// Collation column by name: the number of columns differs between MySQL 5.7,
+// MySQL 8 and the various MariaDB releases.
+func queryDefaultCollationForCharset(ctx context.Context, db *sql.DB, defaultCharset string) (string, error) {
+	stmtSQL := "SHOW COLLATION WHERE `Charset` = ? AND `Default` = 'Yes'"
+	logQuery(stmtSQL)
+	rows, err := db.QueryContext(ctx, stmtSQL, defaultCharset)
+	if err != nil {
+		return "", fmt.Errorf("Error getting default charset: %s, %s", err, defaultCharset)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", fmt.Errorf("Error getting default charset: %s, %s", err, defaultCharset)
+	}
+	collationIndex := -1
+	for i, column := range columns {
+		if strings.EqualFold(column, "Collation") {
+			collationIndex = i
+		}
+	}
+	if collationIndex == -1 {
+		return "", fmt.Errorf("Error getting default charset: no Collation column in %v, %s", columns, defaultCharset)
+	}
 
-			return fmt.Errorf("Error getting default charset: %s, %s", res, defaultCharset)
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return "", fmt.Errorf("Error getting default charset: %s, %s", err, defaultCharset)
 		}
+		return "", fmt.Errorf("Charset %s has no default collation", defaultCharset)
 	}
 
-	d.Set("name", name)
-	d.Set("default_character_set", defaultCharset)
-	d.Set("default_collation", defaultCollation)
+	values := make([]sql.RawBytes, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return "", fmt.Errorf("Error getting default charset: %s, %s", err, defaultCharset)
+	}
 
-	return nil
+	return string(values[collationIndex]), nil
+}
+
+// suppressCaseDiff ignores differences in case only, e.g. of storage engine
+// names, which MySQL accepts in any case.
+func suppressCaseDiff(k, old, new string, d *schema.ResourceData) bool {
+	return strings.EqualFold(old, new)
+}
+
+// suppressCharsetDiff ignores case in charsets and collations, which MySQL
+// reports in lower case, and treats utf8 and the utf8mb3 name MySQL 8.0.30
+// and newer report as equal.
+func suppressCharsetDiff(k, old, new string, d *schema.ResourceData) bool {
+	return normalizeCharsetName(old) == normalizeCharsetName(new)
+}
+
+// normalizeCharsetName lower-cases a charset or collation name and spells
+// utf8mb3 as utf8.
+func normalizeCharsetName(name string) string {
+	name = strings.ToLower(name)
+	if name == "utf8mb3" || strings.HasPrefix(name, "utf8mb3_") {
+		return "utf8" + name[len("utf8mb3"):]
+	}
+	return name
+}
+
+// validateSystemObjectSchema is checkSystemSchema at plan time, for the
+// resources naming their database in a database attribute.
+func validateSystemObjectSchema(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("database") {
+		return nil
+	}
+	return checkSystemSchema(d, d.Get("database").(string), "manage objects in")
+}
+
+// validateManagedSchema rejects plans for databases outside the provider's
+// managed_schema_allowlist. Destroys have no diff to customize, DeleteDb checks
+// again.
+func validateManagedSchema(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("name") {
+		return nil
+	}
+	return meta.(*MySQLConfiguration).checkManagedSchema(d.Get("name").(string))
+}
+
+// charsetChangeForcesNew plans a replacement for a change of key on an existing
+// database when charset_change_forces_new is set.
+func charsetChangeForcesNew(key string) customdiff.ResourceConditionFunc {
+	return func(d *schema.ResourceDiff, meta interface{}) bool {
+		return d.Id() != "" && d.HasChange(key) && d.Get("charset_change_forces_new").(bool)
+	}
+}
+
+// validateCharsetCollation catches a collation that doesn't belong to the
+// configured charset at plan time instead of failing the apply. A collation
+// set without a new charset brings its own charset along, as it does in
+// ALTER DATABASE ... COLLATE, rather than being held against the charset in
+// state or the provider's default.
+func validateCharsetCollation(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.HasChange("default_charset") && !d.HasChange("default_collation") {
+		return nil
+	}
+	if !d.NewValueKnown("default_collation") {
+		return nil
+	}
+
+	defaultCharset := d.Get("default_charset").(string)
+	defaultCollation := d.Get("default_collation").(string)
+	if defaultCollation == "" {
+		return nil
+	}
+
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	stmtSQL := "SELECT `CHARACTER_SET_NAME` FROM `information_schema`.`COLLATIONS` WHERE `COLLATION_NAME` = ?"
+	logQuery(stmtSQL)
+
+	var collationCharset string
+	err := db.QueryRowContext(ctx, stmtSQL, defaultCollation).Scan(&collationCharset)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// Leave unknown collations to the server to report.
+			return nil
+		}
+		return fmt.Errorf("Error looking up collation %s: %s", defaultCollation, err)
+	}
+
+	if normalizeCharsetName(collationCharset) == normalizeCharsetName(defaultCharset) {
+		return nil
+	}
+	if !d.HasChange("default_charset") || !d.NewValueKnown("default_charset") {
+		return d.SetNew("default_charset", collationCharset)
+	}
+	if defaultCharset == "" {
+		return nil
+	}
+
+	return fmt.Errorf("Collation %s belongs to charset %s and can't be used with default_charset %s", defaultCollation, collationCharset, defaultCharset)
 }
 
 func databaseSQLCMD(verb string, d *schema.ResourceData) string {
 	name := d.Get("name").(string)
-	defaultCharset := d.Get("default_character_set").(string)
+	defaultCharset := d.Get("default_charset").(string)
 	defaultCollation := d.Get("default_collation").(string)
+	defaultEncryption := d.Get("default_encryption").(string)
+	comment := d.Get("comment").(string)
 
 	var defaultCharsetClause string
 	var defaultCollationClause string
+	var defaultEncryptionClause string
+	var commentClause string
 
 	if defaultCharset != "" {
 		defaultCharsetClause = defCharSetKey + quoteIdentifier(defaultCharset)
@@ -146,23 +678,127 @@ func databaseSQLCMD(verb string, d *schema.ResourceData) string {
 	if defaultCollation != "" {
 		defaultCollationClause = defaultCollateKey + quoteIdentifier(defaultCollation)
 	}
+	if defaultEncryption != "" {
+		defaultEncryptionClause = defaultEncryptionKey + "'" + defaultEncryption + "'"
+	}
+	if comment != "" {
+		commentClause = "COMMENT " + quoteString(comment)
+	}
 
-	return fmt.Sprintf(
-		"%s DATABASE %s %s %s",
+	if verb == "CREATE" && d.Get("create_if_not_exists").(bool) {
+		verb += " DATABASE IF NOT EXISTS"
+	} else {
+		verb += " DATABASE"
+	}
+
+	return joinClauses(
 		verb,
 		quoteIdentifier(name),
 		defaultCharsetClause,
 		defaultCollationClause,
+		defaultEncryptionClause,
+		commentClause,
 	)
 }
 
+// alterDatabaseSQLCMD only emits the clauses that actually changed. A charset
+// change always carries the collation along, since MySQL would otherwise reset
+// it to the default collation of the new charset.
+func alterDatabaseSQLCMD(d *schema.ResourceData) string {
+	name := d.Get("name").(string)
+	defaultCharset := d.Get("default_charset").(string)
+	defaultCollation := d.Get("default_collation").(string)
+	defaultEncryption := d.Get("default_encryption").(string)
+	comment := d.Get("comment").(string)
+
+	var defaultCharsetClause string
+	var defaultCollationClause string
+	var defaultEncryptionClause string
+	var commentClause string
+
+	if d.HasChange("default_charset") && defaultCharset != "" {
+		defaultCharsetClause = defCharSetKey + quoteIdentifier(defaultCharset)
+	}
+	if (d.HasChange("default_collation") || defaultCharsetClause != "") && defaultCollation != "" {
+		defaultCollationClause = defaultCollateKey + quoteIdentifier(defaultCollation)
+	}
+	if d.HasChange("default_encryption") && defaultEncryption != "" {
+		defaultEncryptionClause = defaultEncryptionKey + "'" + defaultEncryption + "'"
+	}
+	if d.HasChange("comment") {
+		commentClause = "COMMENT " + quoteString(comment)
+	}
+
+	// Emptied attributes fall back to the server defaults, which leaves
+	// nothing to alter.
+	if defaultCharsetClause == "" && defaultCollationClause == "" && defaultEncryptionClause == "" && commentClause == "" {
+		return ""
+	}
+
+	return joinClauses(
+		"ALTER DATABASE",
+		quoteIdentifier(name),
+		defaultCharsetClause,
+		defaultCollationClause,
+		defaultEncryptionClause,
+		commentClause,
+	)
+}
+
+// joinClauses joins the non-empty clauses of a statement with single spaces.
+func joinClauses(clauses ...string) string {
+	var nonEmpty []string
+	for _, clause := range clauses {
+		if clause != "" {
+			nonEmpty = append(nonEmpty, clause)
+		}
+	}
+	return strings.Join(nonEmpty, " ")
+}
+
+var versionCommentRegexp = regexp.MustCompile(`/\*!\d*`)
+var plainCommentRegexp = regexp.MustCompile(`/\*([^!][\s\S]*?)?\*/`)
+
+// normalizeCreateDatabaseSQL drops the quoted database name, which could
+// itself contain the keywords we look for, and unwraps version-gated comments
+// like "/*!40100 DEFAULT CHARACTER SET utf8 */" so their clauses can be parsed
+// the same way on every server version. Plain comments are removed entirely.
+func normalizeCreateDatabaseSQL(createSQL string) string {
+	if start := strings.IndexRune(createSQL, '`'); start != -1 {
+		end := start + 1
+		for end < len(createSQL) {
+			if createSQL[end] == '`' {
+				if end+1 < len(createSQL) && createSQL[end+1] == '`' {
+					end += 2
+					continue
+				}
+				break
+			}
+			end++
+		}
+		if end < len(createSQL) {
+			end++
+		}
+		createSQL = createSQL[:start] + createSQL[end:]
+	}
+
+	createSQL = plainCommentRegexp.ReplaceAllString(createSQL, " ")
+	createSQL = versionCommentRegexp.ReplaceAllString(createSQL, " ")
+	return strings.Replace(createSQL, "*/", " ", -1)
+}
+
 func extractIdentAfter(sql string, keyword string) string {
 	charsetIndex := strings.Index(sql, keyword)
 	if charsetIndex != -1 {
 		charsetIndex += len(keyword)
 		remain := sql[charsetIndex:]
 		spaceIndex := strings.IndexRune(remain, ' ')
-		return remain[:spaceIndex]
+		if spaceIndex != -1 {
+			remain = remain[:spaceIndex]
+		}
+		// The identifier may be the last token of the statement or of a
+		// version-gated comment, e.g. "COLLATE utf8_bin*/".
+		return strings.TrimRight(remain, "*/)")
 	}
 
 	return ""
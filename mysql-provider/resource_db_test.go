@@ -0,0 +1,1107 @@
+package mysql_provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccDatabase_basic(t *testing.T) {
+	name := testAccDatabasePrefix + acctest.RandString(8)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccDatabaseCheckDestroy(name),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabaseConfig(name, "utf8mb4", "utf8mb4_bin"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDatabaseCheckExists("mysql_database.test", name),
+					resource.TestCheckResourceAttr("mysql_database.test", "name", name),
+					resource.TestCheckResourceAttr("mysql_database.test", "default_charset", "utf8mb4"),
+					resource.TestCheckResourceAttr("mysql_database.test", "default_collation", "utf8mb4_bin"),
+				),
+			},
+			{
+				ResourceName:      "mysql_database.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"allow_system_schema",
+					"charset_change_forces_new",
+					"create_if_not_exists",
+					"recreate_on_charset_change",
+				},
+			},
+		},
+	})
+}
+
+func TestAccDatabase_collationChange(t *testing.T) {
+	name := testAccDatabasePrefix + acctest.RandString(8)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccDatabaseCheckDestroy(name),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabaseConfig(name, "utf8mb4", "utf8mb4_bin"),
+				Check:  testAccDatabaseCheckExists("mysql_database.test", name),
+			},
+			{
+				Config: testAccDatabaseConfig(name, "utf8mb4", "utf8mb4_general_ci"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDatabaseCheckExists("mysql_database.test", name),
+					resource.TestCheckResourceAttr("mysql_database.test", "default_collation", "utf8mb4_general_ci"),
+				),
+			},
+		},
+	})
+}
+
+// The utf8mb4_0900 collations were introduced with MySQL 8.0.
+func TestAccDatabase_mysql8Collation(t *testing.T) {
+	name := testAccDatabasePrefix + acctest.RandString(8)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccSkipUnlessMySQL(t, "8.0.0")
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccDatabaseCheckDestroy(name),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabaseConfig(name, "utf8mb4", "utf8mb4_0900_ai_ci"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDatabaseCheckExists("mysql_database.test", name),
+					resource.TestCheckResourceAttr("mysql_database.test", "default_collation", "utf8mb4_0900_ai_ci"),
+				),
+			},
+		},
+	})
+}
+
+// Only MariaDB 10.5 and newer store database comments.
+func TestAccDatabase_comment(t *testing.T) {
+	name := testAccDatabasePrefix + acctest.RandString(8)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccSkipUnlessMariaDB(t, commentMinMariaDBVersion)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccDatabaseCheckDestroy(name),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name    = %q
+  comment = "it's managed by terraform"
+}
+`, name),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDatabaseCheckExists("mysql_database.test", name),
+					resource.TestCheckResourceAttr("mysql_database.test", "comment", "it's managed by terraform"),
+				),
+			},
+		},
+	})
+}
+
+// Changing the charset behind terraform's back must show up as drift.
+func TestAccDatabase_charsetDrift(t *testing.T) {
+	name := testAccDatabasePrefix + acctest.RandString(8)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccDatabaseCheckDestroy(name),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabaseConfig(name, "utf8mb4", "utf8mb4_bin"),
+				Check:  testAccDatabaseCheckExists("mysql_database.test", name),
+			},
+			{
+				PreConfig:          testAccDatabaseExec(t, "ALTER DATABASE "+quoteIdentifier(name)+" CHARACTER SET latin1 COLLATE latin1_swedish_ci"),
+				Config:             testAccDatabaseConfig(name, "utf8mb4", "utf8mb4_bin"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+// The collation is ignored in the second step, so the state holds what the
+// refresh read instead of what the apply would set.
+func TestAccDatabase_refresh(t *testing.T) {
+	name := testAccDatabasePrefix + acctest.RandString(8)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccDatabaseCheckDestroy(name),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabaseConfig(name, "utf8mb4", "utf8mb4_bin"),
+				Check:  testAccDatabaseCheckExists("mysql_database.test", name),
+			},
+			{
+				PreConfig: testAccDatabaseExec(t, "ALTER DATABASE "+quoteIdentifier(name)+" COLLATE utf8mb4_general_ci"),
+				Config: fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name              = %q
+  default_charset   = "utf8mb4"
+  default_collation = "utf8mb4_bin"
+
+  lifecycle {
+    ignore_changes = [default_collation]
+  }
+}
+`, name),
+				Check: resource.TestCheckResourceAttr("mysql_database.test", "default_collation", "utf8mb4_general_ci"),
+			},
+		},
+	})
+}
+
+// A database dropped behind terraform's back is planned to be recreated.
+func TestAccDatabase_droppedOutOfBand(t *testing.T) {
+	name := testAccDatabasePrefix + acctest.RandString(8)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccDatabaseCheckDestroy(name),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabaseConfig(name, "utf8mb4", "utf8mb4_bin"),
+				Check:  testAccDatabaseCheckExists("mysql_database.test", name),
+			},
+			{
+				PreConfig:          testAccDatabaseExec(t, "DROP DATABASE "+quoteIdentifier(name)),
+				Config:             testAccDatabaseConfig(name, "utf8mb4", "utf8mb4_bin"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccDatabaseConfig(name, "utf8mb4", "utf8mb4_bin"),
+				Check:  testAccDatabaseCheckExists("mysql_database.test", name),
+			},
+		},
+	})
+}
+
+// Encryption is left off, turning it on needs a keyring on the server.
+func TestAccDatabase_encryption(t *testing.T) {
+	name := testAccDatabasePrefix + acctest.RandString(8)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccSkipUnlessMySQL(t, "8.0.16")
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccDatabaseCheckDestroy(name),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name               = %q
+  default_encryption = "N"
+}
+`, name),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDatabaseCheckExists("mysql_database.test", name),
+					resource.TestCheckResourceAttr("mysql_database.test", "default_encryption", "N"),
+					testAccCheckRows(1, "SELECT 1 FROM information_schema.SCHEMATA WHERE SCHEMA_NAME = ? AND DEFAULT_ENCRYPTION = 'NO'", name),
+				),
+			},
+		},
+	})
+}
+
+// Databases inherit the provider's charset and collation unless they set
+// their own.
+func TestAccDatabase_providerDefaults(t *testing.T) {
+	name := testAccDatabasePrefix + acctest.RandString(8)
+	own := testAccDatabasePrefix + acctest.RandString(8)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: resource.ComposeTestCheckFunc(testAccDatabaseCheckDestroy(name), testAccDatabaseCheckDestroy(own)),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "mysql" {
+  default_charset   = "latin1"
+  default_collation = "latin1_bin"
+}
+
+resource "mysql_database" "inherited" {
+  name = %q
+}
+
+resource "mysql_database" "own" {
+  name              = %q
+  default_collation = "utf8mb4_general_ci"
+}
+`, name, own),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_database.inherited", "default_charset", "latin1"),
+					resource.TestCheckResourceAttr("mysql_database.inherited", "default_collation", "latin1_bin"),
+					resource.TestCheckResourceAttr("mysql_database.own", "default_charset", "utf8mb4"),
+					resource.TestCheckResourceAttr("mysql_database.own", "default_collation", "utf8mb4_general_ci"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDatabaseConfig(name string, charset string, collation string) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name              = %q
+  default_charset   = %q
+  default_collation = %q
+}
+`, name, charset, collation)
+}
+
+func testAccDatabaseCheckExists(resourceName string, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("Resource %s not found", resourceName)
+		}
+		if rs.Primary.ID != name {
+			return fmt.Errorf("Resource %s has ID %q, want %q", resourceName, rs.Primary.ID, name)
+		}
+
+		db := testAccProvider.Meta().(*MySQLConfiguration).connection()
+		var found string
+		if err := db.QueryRow("SHOW DATABASES LIKE ?", likePatternReplacer.Replace(name)).Scan(&found); err != nil {
+			return fmt.Errorf("Error reading database %s: %s", name, err)
+		}
+		return nil
+	}
+}
+
+func testAccDatabaseCheckDestroy(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		db := testAccProvider.Meta().(*MySQLConfiguration).connection()
+		rows, err := db.Query("SHOW DATABASES LIKE ?", likePatternReplacer.Replace(name))
+		if err != nil {
+			return fmt.Errorf("Error listing databases: %s", err)
+		}
+		defer rows.Close()
+		if rows.Next() {
+			return fmt.Errorf("Database %s still exists", name)
+		}
+		return rows.Err()
+	}
+}
+
+// testAccDatabaseExec returns a PreConfig running stmtSQL on the test server,
+// to change things behind terraform's back.
+func testAccDatabaseExec(t *testing.T, stmtSQL string) func() {
+	return func() {
+		db := testAccProvider.Meta().(*MySQLConfiguration).connection()
+		if _, err := db.Exec(stmtSQL); err != nil {
+			t.Fatalf("Error running %q: %s", stmtSQL, err)
+		}
+	}
+}
+
+func TestDatabaseSQLCMD(t *testing.T) {
+	cases := []struct {
+		raw      map[string]interface{}
+		verb     string
+		expected string
+	}{
+		{
+			map[string]interface{}{"name": "app"},
+			"CREATE",
+			"CREATE DATABASE `app`",
+		},
+		{
+			map[string]interface{}{"name": "app", "create_if_not_exists": true},
+			"CREATE",
+			"CREATE DATABASE IF NOT EXISTS `app`",
+		},
+		{
+			map[string]interface{}{
+				"name":              "app",
+				"default_charset":   "utf8mb4",
+				"default_collation": "utf8mb4_bin",
+				"comment":           "it's here",
+			},
+			"CREATE",
+			"CREATE DATABASE `app` CHARACTER SET `utf8mb4` COLLATE `utf8mb4_bin` COMMENT 'it''s here'",
+		},
+		{
+			map[string]interface{}{"name": "app", "default_encryption": "Y"},
+			"CREATE",
+			"CREATE DATABASE `app` ENCRYPTION='Y'",
+		},
+		{
+			map[string]interface{}{"name": "app", "default_charset": "", "default_collation": ""},
+			"CREATE",
+			"CREATE DATABASE `app`",
+		},
+		{
+			map[string]interface{}{"name": "app", "default_charset": "latin1", "default_collation": ""},
+			"CREATE",
+			"CREATE DATABASE `app` CHARACTER SET `latin1`",
+		},
+	}
+	for _, c := range cases {
+		d := schema.TestResourceDataRaw(t, ResourceDB().Schema, c.raw)
+		if got := databaseSQLCMD(c.verb, d); got != c.expected {
+			t.Errorf("databaseSQLCMD(%q, %v) = %q, want %q", c.verb, c.raw, got, c.expected)
+		}
+	}
+}
+
+func TestDeleteDb(t *testing.T) {
+	cases := map[string]fakeResult{
+		"dropped":         {},
+		"already dropped": fakeError(dropUnknownDatabaseErr, "Can't drop database 'app'; database doesn't exist"),
+	}
+	for name, result := range cases {
+		result := result
+		server := newFakeServer(t, func(query string) fakeResult {
+			if strings.HasPrefix(query, "DROP DATABASE") {
+				return result
+			}
+			return fakeDefaultResult(query)
+		})
+		conf, err := testProviderConfigure(t, map[string]interface{}{"endpoint": server.addr()})
+		if err != nil {
+			t.Fatalf("providerConfigure returned %s", err)
+		}
+
+		d := schema.TestResourceDataRaw(t, ResourceDB().Schema, map[string]interface{}{"name": "app"})
+		d.SetId("app")
+		if err := DeleteDb(d, conf); err != nil {
+			t.Errorf("%s: DeleteDb returned %s", name, err)
+		}
+		if d.Id() != "" {
+			t.Errorf("%s: DeleteDb left the ID %q", name, d.Id())
+		}
+		if !fakeQueriesContain(server.receivedQueries(), "DROP DATABASE IF EXISTS `app`") {
+			t.Errorf("%s: DeleteDb didn't drop the database, got %q", name, server.receivedQueries())
+		}
+	}
+
+	server := newFakeServer(t, func(query string) fakeResult {
+		if strings.HasPrefix(query, "DROP DATABASE") {
+			return fakeError(1044, "Access denied")
+		}
+		return fakeDefaultResult(query)
+	})
+	conf, err := testProviderConfigure(t, map[string]interface{}{"endpoint": server.addr()})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+	d := schema.TestResourceDataRaw(t, ResourceDB().Schema, map[string]interface{}{"name": "app"})
+	d.SetId("app")
+	if err := DeleteDb(d, conf); err == nil {
+		t.Error("DeleteDb ignored an access denied error")
+	}
+}
+
+func TestAlterDatabaseSQLCMD(t *testing.T) {
+	state := map[string]string{
+		"name":              "app",
+		"default_charset":   "utf8mb4",
+		"default_collation": "utf8mb4_bin",
+	}
+	cases := []struct {
+		raw      map[string]interface{}
+		expected string
+	}{
+		{
+			map[string]interface{}{"name": "app", "default_charset": "utf8mb4", "default_collation": "utf8mb4_bin"},
+			"",
+		},
+		{
+			map[string]interface{}{"name": "app", "default_charset": "utf8mb4", "default_collation": "utf8mb4_general_ci"},
+			"ALTER DATABASE `app` COLLATE `utf8mb4_general_ci`",
+		},
+		{
+			map[string]interface{}{"name": "app", "default_charset": "latin1", "default_collation": "utf8mb4_bin"},
+			"ALTER DATABASE `app` CHARACTER SET `latin1` COLLATE `utf8mb4_bin`",
+		},
+		{
+			map[string]interface{}{"name": "app", "default_charset": "utf8mb4", "default_collation": "utf8mb4_bin", "default_encryption": "Y"},
+			"ALTER DATABASE `app` ENCRYPTION='Y'",
+		},
+	}
+	for _, c := range cases {
+		d := testResourceDataDiff(t, ResourceDB(), state, c.raw)
+		if got := alterDatabaseSQLCMD(d); got != c.expected {
+			t.Errorf("alterDatabaseSQLCMD(%v) = %q, want %q", c.raw, got, c.expected)
+		}
+	}
+}
+
+// testResourceDataDiff is the resource data of an update from state to the
+// raw configuration, unlike schema.TestResourceDataRaw's, which starts out
+// without state.
+func testResourceDataDiff(t *testing.T, r *schema.Resource, state map[string]string, raw map[string]interface{}) *schema.ResourceData {
+	s := &terraform.InstanceState{ID: state["name"], Attributes: state}
+	diff, err := schema.InternalMap(r.Schema).Diff(s, terraform.NewResourceConfigRaw(raw), nil, nil, true)
+	if err != nil {
+		t.Fatalf("Error computing the diff: %s", err)
+	}
+	d, err := schema.InternalMap(r.Schema).Data(s, diff)
+	if err != nil {
+		t.Fatalf("Error reading the diff: %s", err)
+	}
+	return d
+}
+
+func TestExtractIdentAfter(t *testing.T) {
+	cases := []struct {
+		sql      string
+		keyword  string
+		expected string
+	}{
+		{"CREATE DATABASE x DEFAULT CHARACTER SET utf8mb4 COLLATE utf8mb4_bin", defaultCollateKey, "utf8mb4_bin"},
+		{"CREATE DATABASE x DEFAULT CHARACTER SET utf8mb4 COLLATE utf8mb4_bin", defCharSetKey, "utf8mb4"},
+		{"CREATE DATABASE x /*!40100 DEFAULT CHARACTER SET latin1 COLLATE latin1_bin*/", defaultCollateKey, "latin1_bin"},
+		{"CREATE DATABASE x (DEFAULT CHARACTER SET latin1)", defCharSetKey, "latin1"},
+		{"CREATE DATABASE x COLLATE ", defaultCollateKey, ""},
+		{"CREATE DATABASE x", defaultCollateKey, ""},
+	}
+	for _, c := range cases {
+		if got := extractIdentAfter(c.sql, c.keyword); got != c.expected {
+			t.Errorf("extractIdentAfter(%q, %q) = %q, want %q", c.sql, c.keyword, got, c.expected)
+		}
+	}
+}
+
+func TestNormalizeCreateDatabaseSQL(t *testing.T) {
+	cases := []struct {
+		createSQL  string
+		charset    string
+		collation  string
+		encryption string
+	}{
+		// MySQL 8.0
+		{
+			"CREATE DATABASE `app` /*!40100 DEFAULT CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci */ /*!80016 DEFAULT ENCRYPTION='N' */",
+			"utf8mb4", "utf8mb4_0900_ai_ci", "'N'",
+		},
+		// MySQL 5.7, the collation is left out when it is the charset's default.
+		{
+			"CREATE DATABASE `app` /*!40100 DEFAULT CHARACTER SET latin1 */",
+			"latin1", "", "",
+		},
+		// Names that look like clauses must not be picked up.
+		{
+			"CREATE DATABASE `COLLATE x` /*!40100 DEFAULT CHARACTER SET utf8mb4 COLLATE utf8mb4_bin */ /*!80016 DEFAULT ENCRYPTION='Y' */",
+			"utf8mb4", "utf8mb4_bin", "'Y'",
+		},
+		{
+			"CREATE DATABASE `a``CHARACTER SET b` /*!40100 DEFAULT CHARACTER SET utf8 */",
+			"utf8", "", "",
+		},
+	}
+	for _, c := range cases {
+		normalized := normalizeCreateDatabaseSQL(c.createSQL)
+		if got := extractIdentAfter(normalized, defCharSetKey); got != c.charset {
+			t.Errorf("charset of %q = %q, want %q", c.createSQL, got, c.charset)
+		}
+		if got := extractIdentAfter(normalized, defaultCollateKey); got != c.collation {
+			t.Errorf("collation of %q = %q, want %q", c.createSQL, got, c.collation)
+		}
+		if got := extractIdentAfter(normalized, defaultEncryptionKey); got != c.encryption {
+			t.Errorf("encryption of %q = %q, want %q", c.createSQL, got, c.encryption)
+		}
+	}
+}
+
+func TestCreateDbEncryptionVersionCheck(t *testing.T) {
+	server := newFakeServer(t, func(query string) fakeResult {
+		if query == "SELECT @@GLOBAL.version" {
+			return fakeRow("@@GLOBAL.version", "5.7.44-log")
+		}
+		return fakeDefaultResult(query)
+	})
+	conf, err := testProviderConfigure(t, map[string]interface{}{"endpoint": server.addr()})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+
+	d := schema.TestResourceDataRaw(t, ResourceDB().Schema, map[string]interface{}{"name": "app", "default_encryption": "Y"})
+	err = CreateDb(d, conf)
+	if err == nil || !strings.Contains(err.Error(), "default_encryption requires MySQL 8.0.16 or newer") {
+		t.Errorf("CreateDb with default_encryption on MySQL 5.7 returned %v", err)
+	}
+	if fakeQueriesContain(server.receivedQueries(), "CREATE DATABASE") {
+		t.Errorf("CreateDb ran CREATE DATABASE on MySQL 5.7, got %q", server.receivedQueries())
+	}
+}
+
+func TestValidateCharsetCollation(t *testing.T) {
+	server := newFakeServer(t, func(query string) fakeResult {
+		if strings.Contains(query, "`information_schema`.`COLLATIONS`") {
+			collation := strings.Trim(query[strings.LastIndex(query, "= ")+2:], "'")
+			return fakeRow("CHARACTER_SET_NAME", strings.SplitN(collation, "_", 2)[0])
+		}
+		return fakeDefaultResult(query)
+	})
+	conf, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint":           server.addr(),
+		"interpolate_params": true,
+	})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+
+	state := map[string]string{
+		"name":              "app",
+		"default_charset":   "utf8mb4",
+		"default_collation": "utf8mb4_bin",
+	}
+	cases := []struct {
+		state   map[string]string
+		raw     map[string]interface{}
+		charset string
+		err     string
+	}{
+		{nil, map[string]interface{}{"name": "app", "default_charset": "utf8mb4", "default_collation": "utf8mb4_bin"}, "utf8mb4", ""},
+		{nil, map[string]interface{}{"name": "app", "default_charset": "utf8mb4", "default_collation": "latin1_swedish_ci"}, "", "belongs to charset latin1"},
+		{nil, map[string]interface{}{"name": "app", "default_collation": "latin1_bin"}, "latin1", ""},
+		{state, map[string]interface{}{"name": "app", "default_collation": "latin1_bin"}, "latin1", ""},
+		{state, map[string]interface{}{"name": "app", "default_charset": "latin1", "default_collation": "utf8mb4_bin"}, "", "belongs to charset utf8mb4"},
+	}
+	for _, c := range cases {
+		diff, err := testResourceDiff(t, ResourceDB(), c.state, c.raw, conf)
+		if c.err != "" {
+			if err == nil || !strings.Contains(err.Error(), c.err) {
+				t.Errorf("diff of %v returned %v, want an error mentioning %q", c.raw, err, c.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("diff of %v returned %s", c.raw, err)
+			continue
+		}
+		if got := diff.Attributes["default_charset"].New; got != c.charset {
+			t.Errorf("diff of %v plans default_charset %q, want %q", c.raw, got, c.charset)
+		}
+	}
+}
+
+// testResourceDiff plans the update from state to the raw configuration,
+// CustomizeDiff included. A nil state plans a create.
+func testResourceDiff(t *testing.T, r *schema.Resource, state map[string]string, raw map[string]interface{}, meta interface{}) (*terraform.InstanceDiff, error) {
+	var s *terraform.InstanceState
+	if state != nil {
+		s = &terraform.InstanceState{ID: state["name"], Attributes: state}
+	}
+	return schema.InternalMap(r.Schema).Diff(s, terraform.NewResourceConfigRaw(raw), r.CustomizeDiff, meta, true)
+}
+
+func TestQueryDefaultCollationForCharset(t *testing.T) {
+	// MySQL 5.7 and 8.0 return six columns, MariaDB 10.10 and newer seven.
+	cases := map[string][]string{
+		"mysql":   {"Collation", "Charset", "Id", "Default", "Compiled", "Sortlen"},
+		"mariadb": {"Collation", "Charset", "Id", "Default", "Compiled", "Sortlen", "Pad_attribute"},
+	}
+	for flavour, names := range cases {
+		names := names
+		server := newFakeServer(t, func(query string) fakeResult {
+			if !strings.HasPrefix(query, "SHOW COLLATION") {
+				return fakeDefaultResult(query)
+			}
+			result := fakeResult{rows: [][]interface{}{make([]interface{}, len(names))}}
+			for i, name := range names {
+				result.columns = append(result.columns, fakeColumn{name, fakeTypeVarString})
+				result.rows[0][i] = "x"
+			}
+			result.rows[0][0] = "latin1_swedish_ci"
+			return result
+		})
+		conf, err := testProviderConfigure(t, map[string]interface{}{
+			"endpoint":           server.addr(),
+			"interpolate_params": true,
+		})
+		if err != nil {
+			t.Fatalf("providerConfigure returned %s", err)
+		}
+
+		collation, err := queryDefaultCollationForCharset(context.Background(), conf.connection(), "latin1")
+		if err != nil {
+			t.Errorf("%s: queryDefaultCollationForCharset returned %s", flavour, err)
+			continue
+		}
+		if collation != "latin1_swedish_ci" {
+			t.Errorf("%s: queryDefaultCollationForCharset = %q, want latin1_swedish_ci", flavour, collation)
+		}
+	}
+}
+
+func TestUpdateDbRecreate(t *testing.T) {
+	server := newFakeServer(t, func(query string) fakeResult {
+		if query == "SHOW CREATE DATABASE `app`" {
+			return fakeResult{
+				columns: []fakeColumn{{"Database", fakeTypeVarString}, {"Create Database", fakeTypeVarString}},
+				rows:    [][]interface{}{{"app", "CREATE DATABASE `app` /*!40100 DEFAULT CHARACTER SET latin1 COLLATE latin1_bin */"}},
+			}
+		}
+		return fakeDefaultResult(query)
+	})
+	conf, err := testProviderConfigure(t, map[string]interface{}{"endpoint": server.addr()})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+
+	state := map[string]string{
+		"name":              "app",
+		"default_charset":   "utf8mb4",
+		"default_collation": "utf8mb4_bin",
+	}
+	d := testResourceDataDiff(t, ResourceDB(), state, map[string]interface{}{
+		"name":                       "app",
+		"default_charset":            "latin1",
+		"default_collation":          "latin1_bin",
+		"recreate_on_charset_change": true,
+	})
+	if err := UpdateDb(d, conf); err != nil {
+		t.Fatalf("UpdateDb returned %s", err)
+	}
+
+	var statements []string
+	for _, query := range server.receivedQueries() {
+		if strings.HasPrefix(query, "DROP") || strings.HasPrefix(query, "CREATE") || strings.HasPrefix(query, "ALTER") {
+			statements = append(statements, query)
+		}
+	}
+	expected := []string{
+		"DROP DATABASE `app`",
+		"CREATE DATABASE `app` CHARACTER SET `latin1` COLLATE `latin1_bin`",
+	}
+	if !reflect.DeepEqual(statements, expected) {
+		t.Errorf("UpdateDb ran %q, want %q", statements, expected)
+	}
+	if got := d.Get("default_charset").(string); got != "latin1" {
+		t.Errorf("default_charset = %q after the recreate, want latin1", got)
+	}
+}
+
+func TestExecDDLLogsWarnings(t *testing.T) {
+	server := newFakeServer(t, func(query string) fakeResult {
+		if query == "SHOW WARNINGS" {
+			return fakeResult{
+				columns: []fakeColumn{{"Level", fakeTypeVarString}, {"Code", fakeTypeLongLong}, {"Message", fakeTypeVarString}},
+				rows:    [][]interface{}{{"Warning", "1287", "'utf8' is deprecated and will be removed in a future release."}},
+			}
+		}
+		return fakeDefaultResult(query)
+	})
+	conf, err := testProviderConfigure(t, map[string]interface{}{"endpoint": server.addr()})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+
+	output := testCaptureLog(t)
+	if err := execDDL(context.Background(), conf.connection(), "CREATE DATABASE `app` CHARACTER SET `utf8`"); err != nil {
+		t.Fatalf("execDDL returned %s", err)
+	}
+	expected := "[WARN] MySQL Warning 1287: 'utf8' is deprecated"
+	if !strings.Contains(output.String(), expected) {
+		t.Errorf("execDDL logged %q, want it to contain %q", output.String(), expected)
+	}
+}
+
+func TestApplyProviderCharsetDefaults(t *testing.T) {
+	conf := &MySQLConfiguration{DefaultCharset: "utf8mb4", DefaultCollation: "utf8mb4_bin"}
+	cases := []struct {
+		raw       map[string]interface{}
+		charset   string
+		collation string
+	}{
+		{map[string]interface{}{}, "utf8mb4", "utf8mb4_bin"},
+		{map[string]interface{}{"default_charset": "utf8mb4"}, "utf8mb4", "utf8mb4_bin"},
+		{map[string]interface{}{"default_charset": "latin1"}, "latin1", ""},
+		{map[string]interface{}{"default_collation": "latin1_bin"}, "", "latin1_bin"},
+		{map[string]interface{}{"default_charset": "latin1", "default_collation": "latin1_bin"}, "latin1", "latin1_bin"},
+	}
+	for _, c := range cases {
+		c.raw["name"] = "app"
+		d := schema.TestResourceDataRaw(t, ResourceDB().Schema, c.raw)
+		applyProviderCharsetDefaults(d, conf)
+		if got := d.Get("default_charset").(string); got != c.charset {
+			t.Errorf("applyProviderCharsetDefaults(%v) charset = %q, want %q", c.raw, got, c.charset)
+		}
+		if got := d.Get("default_collation").(string); got != c.collation {
+			t.Errorf("applyProviderCharsetDefaults(%v) collation = %q, want %q", c.raw, got, c.collation)
+		}
+	}
+}
+
+func TestNormalizeCharsetName(t *testing.T) {
+	cases := map[string]string{
+		"utf8":               "utf8",
+		"UTF8MB4":            "utf8mb4",
+		"utf8mb3":            "utf8",
+		"utf8mb3_general_ci": "utf8_general_ci",
+		"utf8mb4_0900_ai_ci": "utf8mb4_0900_ai_ci",
+		"latin1_Swedish_CI":  "latin1_swedish_ci",
+		"utf8mb3x":           "utf8mb3x",
+		"":                   "",
+	}
+	for in, expected := range cases {
+		if got := normalizeCharsetName(in); got != expected {
+			t.Errorf("normalizeCharsetName(%q) = %q, want %q", in, got, expected)
+		}
+	}
+}
+
+func TestCharsetCaseDiff(t *testing.T) {
+	server := newFakeServer(t, func(query string) fakeResult {
+		if strings.Contains(query, "`information_schema`.`COLLATIONS`") {
+			return fakeRow("CHARACTER_SET_NAME", "utf8mb4")
+		}
+		return fakeDefaultResult(query)
+	})
+	conf, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint":           server.addr(),
+		"interpolate_params": true,
+	})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+
+	state := map[string]string{
+		"name":                       "app",
+		"default_charset":            "utf8mb4",
+		"default_collation":          "utf8mb4_unicode_ci",
+		"allow_system_schema":        "false",
+		"charset_change_forces_new":  "false",
+		"create_if_not_exists":       "false",
+		"recreate_on_charset_change": "false",
+	}
+	raw := map[string]interface{}{
+		"name":              "app",
+		"default_charset":   "UTF8MB4",
+		"default_collation": "UTF8MB4_Unicode_CI",
+	}
+	diff, err := testResourceDiff(t, ResourceDB(), state, raw, conf)
+	if err != nil {
+		t.Fatalf("diff returned %s", err)
+	}
+	if diff != nil && len(diff.Attributes) != 0 {
+		t.Errorf("diff of a case change = %v, want an empty plan", diff.Attributes)
+	}
+}
+
+func TestCharsetChangeForcesNew(t *testing.T) {
+	server := newFakeServer(t, func(query string) fakeResult {
+		if strings.Contains(query, "`information_schema`.`COLLATIONS`") {
+			return fakeRow("CHARACTER_SET_NAME", "utf8mb4")
+		}
+		return fakeDefaultResult(query)
+	})
+	conf, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint":           server.addr(),
+		"interpolate_params": true,
+	})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+
+	for _, forcesNew := range []bool{false, true} {
+		state := map[string]string{
+			"name":                       "app",
+			"default_charset":            "utf8mb4",
+			"default_collation":          "utf8mb4_bin",
+			"allow_system_schema":        "false",
+			"charset_change_forces_new":  fmt.Sprint(forcesNew),
+			"create_if_not_exists":       "false",
+			"recreate_on_charset_change": "false",
+		}
+		raw := map[string]interface{}{
+			"name":                      "app",
+			"default_charset":           "utf8mb4",
+			"default_collation":         "utf8mb4_unicode_ci",
+			"charset_change_forces_new": forcesNew,
+		}
+		diff, err := testResourceDiff(t, ResourceDB(), state, raw, conf)
+		if err != nil {
+			t.Fatalf("diff with charset_change_forces_new = %t returned %s", forcesNew, err)
+		}
+		collation, ok := diff.Attributes["default_collation"]
+		if !ok {
+			t.Fatalf("diff with charset_change_forces_new = %t = %v, want a collation change", forcesNew, diff.Attributes)
+		}
+		if collation.RequiresNew != forcesNew {
+			t.Errorf("collation change with charset_change_forces_new = %t has RequiresNew = %t", forcesNew, collation.RequiresNew)
+		}
+	}
+}
+
+func TestCreateDbTimeout(t *testing.T) {
+	server := newFakeServer(t, func(query string) fakeResult {
+		if strings.HasPrefix(query, "CREATE DATABASE") {
+			time.Sleep(3 * time.Second)
+		}
+		return fakeDefaultResult(query)
+	})
+	conf, err := testProviderConfigure(t, map[string]interface{}{"endpoint": server.addr()})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+
+	r := ResourceDB()
+	r.Timeouts.Create = schema.DefaultTimeout(time.Second)
+	d := r.Data(nil)
+	d.Set("name", "app")
+
+	start := time.Now()
+	err = CreateDb(d, conf)
+	if err == nil || !strings.Contains(err.Error(), context.DeadlineExceeded.Error()) {
+		t.Errorf("CreateDb past its timeout returned %v, want %s", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("CreateDb returned after %s, want it cancelled after 1s", elapsed)
+	}
+}
+
+func TestAccDatabase_createIfNotExists(t *testing.T) {
+	name := testAccDatabasePrefix + acctest.RandString(8)
+	config := func(createIfNotExists bool) string {
+		return fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name                 = %q
+  create_if_not_exists = %t
+}
+`, name, createIfNotExists)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			stmtSQL := "CREATE DATABASE " + quoteIdentifier(name)
+			if _, err := testAccConfiguration(t).connection().Exec(stmtSQL); err != nil {
+				t.Fatalf("Error running %q: %s", stmtSQL, err)
+			}
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccDatabaseCheckDestroy(name),
+		Steps: []resource.TestStep{
+			{
+				Config:      config(false),
+				ExpectError: regexp.MustCompile("already exists"),
+			},
+			{
+				Config: config(true),
+				Check:  testAccDatabaseCheckExists("mysql_database.test", name),
+			},
+		},
+	})
+}
+
+func TestCreateDbExisting(t *testing.T) {
+	server := newFakeServer(t, func(query string) fakeResult {
+		if strings.HasPrefix(query, "CREATE DATABASE `app`") {
+			return fakeError(databaseExistsErr, "Can't create database 'app'; database exists")
+		}
+		if strings.Contains(query, "SHOW CREATE DATABASE") {
+			return fakeResult{
+				columns: []fakeColumn{{"Database", fakeTypeVarString}, {"Create Database", fakeTypeVarString}},
+				rows:    [][]interface{}{{"app", "CREATE DATABASE `app` /*!40100 DEFAULT CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci */"}},
+			}
+		}
+		return fakeDefaultResult(query)
+	})
+	conf, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint":           server.addr(),
+		"interpolate_params": true,
+	})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+
+	d := schema.TestResourceDataRaw(t, ResourceDB().Schema, map[string]interface{}{"name": "app"})
+	err = CreateDb(d, conf)
+	if err == nil || !strings.Contains(err.Error(), "terraform import") {
+		t.Errorf("strict CreateDb of an existing database returned %v, want the import hint", err)
+	}
+
+	d = schema.TestResourceDataRaw(t, ResourceDB().Schema, map[string]interface{}{"name": "app", "create_if_not_exists": true})
+	if err := CreateDb(d, conf); err != nil {
+		t.Errorf("CreateDb with create_if_not_exists of an existing database returned %s", err)
+	}
+	if d.Id() != "app" {
+		t.Errorf("CreateDb with create_if_not_exists set the ID %q, want app", d.Id())
+	}
+	if !fakeQueriesContain(server.receivedQueries(), "CREATE DATABASE IF NOT EXISTS `app`") {
+		t.Errorf("CreateDb with create_if_not_exists didn't issue CREATE DATABASE IF NOT EXISTS, got %q", server.receivedQueries())
+	}
+}
+
+func TestCreateDbUnknownCollation(t *testing.T) {
+	server := newFakeServer(t, func(query string) fakeResult {
+		if strings.HasPrefix(query, "CREATE DATABASE `app`") {
+			return fakeError(unknownCollationErr, "Unknown collation: 'utf8mb4_bogus'")
+		}
+		return fakeDefaultResult(query)
+	})
+	conf, err := testProviderConfigure(t, map[string]interface{}{"endpoint": server.addr()})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+
+	d := schema.TestResourceDataRaw(t, ResourceDB().Schema, map[string]interface{}{"name": "app", "default_collation": "utf8mb4_bogus"})
+	err = CreateDb(d, conf)
+	if err == nil || !strings.Contains(err.Error(), `Unknown collation "utf8mb4_bogus"`) || !strings.Contains(err.Error(), "SHOW COLLATION") {
+		t.Errorf("CreateDb with an unknown collation returned %v, want the SHOW COLLATION hint", err)
+	}
+	if d.Id() != "" {
+		t.Errorf("failed CreateDb set the ID %q", d.Id())
+	}
+}
+
+func TestCreateDbKeepsIDWhenReadFails(t *testing.T) {
+	server := newFakeServer(t, func(query string) fakeResult {
+		if strings.HasPrefix(query, "SHOW CREATE DATABASE") {
+			return fakeError(1227, "Access denied; you need the SHOW DATABASES privilege")
+		}
+		return fakeDefaultResult(query)
+	})
+	conf, err := testProviderConfigure(t, map[string]interface{}{"endpoint": server.addr()})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+
+	d := schema.TestResourceDataRaw(t, ResourceDB().Schema, map[string]interface{}{"name": "app"})
+	if err := CreateDb(d, conf); err != nil {
+		t.Errorf("CreateDb returned %s although the database was created", err)
+	}
+	if d.Id() != "app" {
+		t.Errorf("CreateDb set the ID %q, want app so the database isn't orphaned", d.Id())
+	}
+}
+
+func TestReadDbShowCreateDenied(t *testing.T) {
+	server := newFakeServer(t, func(query string) fakeResult {
+		if strings.HasPrefix(query, "SHOW CREATE DATABASE") {
+			return fakeError(databaseAccessDeniedErr, "Access denied for user 'tf'@'%' to database 'app'")
+		}
+		if strings.Contains(query, "`information_schema`.`SCHEMATA`") && strings.Contains(query, "'app'") {
+			return fakeResult{
+				columns: []fakeColumn{
+					{"DEFAULT_CHARACTER_SET_NAME", fakeTypeVarString},
+					{"DEFAULT_COLLATION_NAME", fakeTypeVarString},
+					{"DEFAULT_ENCRYPTION", fakeTypeVarString},
+				},
+				rows: [][]interface{}{{"utf8mb4", "utf8mb4_bin", "NO"}},
+			}
+		}
+		return fakeDefaultResult(query)
+	})
+	conf, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint":           server.addr(),
+		"interpolate_params": true,
+	})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+
+	d := ResourceDB().Data(nil)
+	d.SetId("app")
+	if err := ReadDb(d, conf); err != nil {
+		t.Fatalf("ReadDb returned %s", err)
+	}
+	expected := map[string]string{
+		"name":               "app",
+		"default_charset":    "utf8mb4",
+		"default_collation":  "utf8mb4_bin",
+		"default_encryption": "NO",
+	}
+	for k, v := range expected {
+		if got := d.Get(k).(string); got != v {
+			t.Errorf("ReadDb read %s = %q, want %q", k, got, v)
+		}
+	}
+
+	// Without a row the account can't see the database, which isn't the same
+	// as it being gone.
+	d = ResourceDB().Data(nil)
+	d.SetId("hidden")
+	err = ReadDb(d, conf)
+	if err == nil || !strings.Contains(err.Error(), "not visible to the configured user") {
+		t.Errorf("ReadDb of a hidden database returned %v", err)
+	}
+	if d.Id() != "hidden" {
+		t.Errorf("ReadDb of a hidden database dropped the ID")
+	}
+}
+
+func TestCheckSystemSchema(t *testing.T) {
+	cases := []struct {
+		name    string
+		allow   bool
+		wantErr bool
+	}{
+		{"app", false, false},
+		{"mysql", false, true},
+		{"Performance_Schema", false, true},
+		{"mysql", true, false},
+	}
+	for _, c := range cases {
+		d := schema.TestResourceDataRaw(t, ResourceDB().Schema, map[string]interface{}{
+			"name":                c.name,
+			"allow_system_schema": c.allow,
+		})
+		err := checkSystemSchema(d, c.name, "drop")
+		if (err != nil) != c.wantErr {
+			t.Errorf("checkSystemSchema(%q, allow=%t) returned %v, want error %t", c.name, c.allow, err, c.wantErr)
+		}
+	}
+}
+
+func TestDeleteDbSystemSchema(t *testing.T) {
+	server := newFakeServer(t, nil)
+	conf, err := testProviderConfigure(t, map[string]interface{}{"endpoint": server.addr()})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+
+	d := schema.TestResourceDataRaw(t, ResourceDB().Schema, map[string]interface{}{"name": "mysql"})
+	d.SetId("mysql")
+	err = DeleteDb(d, conf)
+	if err == nil || !strings.Contains(err.Error(), "allow_system_schema") {
+		t.Errorf("DeleteDb of mysql returned %v, want it refused", err)
+	}
+	if fakeQueriesContain(server.receivedQueries(), "DROP DATABASE") {
+		t.Errorf("DeleteDb of mysql ran %q", server.receivedQueries())
+	}
+
+	d = schema.TestResourceDataRaw(t, ResourceDB().Schema, map[string]interface{}{"name": "mysql", "allow_system_schema": true})
+	d.SetId("mysql")
+	if err := DeleteDb(d, conf); err != nil {
+		t.Errorf("DeleteDb of mysql with allow_system_schema returned %s", err)
+	}
+	if !fakeQueriesContain(server.receivedQueries(), "DROP DATABASE IF EXISTS `mysql`") {
+		t.Errorf("DeleteDb with allow_system_schema didn't drop mysql, got %q", server.receivedQueries())
+	}
+}
@@ -0,0 +1,174 @@
+package mysql_provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func ResourceDefaultRoles() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"user": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			// Defaults to the provider's default_user_host.
+			"host": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Computed: true,
+			},
+			// Roles are given as "<name>" or "<name>@<host>", the host
+			// defaulting to "%" like for mysql_role.
+			"roles": {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      hashRoleName,
+			},
+		},
+		SchemaVersion:  0,
+		MigrateState:   nil,
+		StateUpgraders: nil,
+		Create:         CreateDefaultRoles,
+		Read:           ReadDefaultRoles,
+		Update:         UpdateDefaultRoles,
+		Delete:         DeleteDefaultRoles,
+		Exists:         nil,
+		CustomizeDiff:  nil,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		DeprecationMessage: "",
+		Timeouts:           nil,
+		Description:        "",
+	}
+}
+
+func CreateDefaultRoles(d *schema.ResourceData, meta interface{}) error {
+	if err := requireMySQL8(meta.(*MySQLConfiguration), "mysql_default_roles"); err != nil {
+		return err
+	}
+
+	applyDefaultUserHost(d, "host", meta)
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+
+	err := setDefaultRoles(meta, user, host, d.Get("roles").(*schema.Set).List())
+	if err != nil {
+		return err
+	}
+	d.SetId(fmt.Sprintf("%s@%s", user, host))
+
+	return ReadDefaultRoles(d, meta)
+}
+
+func ReadDefaultRoles(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	user, host := splitAccountID(d.Id())
+
+	stmtSQL := "SELECT `DEFAULT_ROLE_USER`, `DEFAULT_ROLE_HOST` FROM `mysql`.`default_roles` WHERE `USER` = ? AND `HOST` = ?"
+	logQuery(stmtSQL)
+
+	rows, err := db.QueryContext(ctx, stmtSQL, user, host)
+	if err != nil {
+		return fmt.Errorf("Error reading default roles of %s: %s", accountName(user, host), err)
+	}
+	defer rows.Close()
+
+	roles := []interface{}{}
+	for rows.Next() {
+		var roleUser, roleHost string
+		if err := rows.Scan(&roleUser, &roleHost); err != nil {
+			return fmt.Errorf("Error reading default roles of %s: %s", accountName(user, host), err)
+		}
+		roles = append(roles, normalizeRoleName(fmt.Sprintf("%s@%s", roleUser, roleHost)))
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("Error reading default roles of %s: %s", accountName(user, host), err)
+	}
+
+	if len(roles) == 0 {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("user", user)
+	d.Set("host", host)
+	d.Set("roles", schema.NewSet(hashRoleName, roles))
+
+	return nil
+}
+
+func UpdateDefaultRoles(d *schema.ResourceData, meta interface{}) error {
+	if d.HasChange("roles") {
+		user, host := splitAccountID(d.Id())
+		err := setDefaultRoles(meta, user, host, d.Get("roles").(*schema.Set).List())
+		if err != nil {
+			return err
+		}
+	}
+
+	return ReadDefaultRoles(d, meta)
+}
+
+func DeleteDefaultRoles(d *schema.ResourceData, meta interface{}) error {
+	user, host := splitAccountID(d.Id())
+
+	err := setDefaultRoles(meta, user, host, nil)
+	if err != nil {
+		return err
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// setDefaultRoles replaces the default roles of the account, no roles at all
+// clears them.
+func setDefaultRoles(meta interface{}, user string, host string, roles []interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	roleList := "NONE"
+	if len(roles) > 0 {
+		names := make([]string, 0, len(roles))
+		for _, role := range roles {
+			names = append(names, accountName(splitAccountID(role.(string))))
+		}
+		roleList = strings.Join(names, ", ")
+	}
+
+	stmtSQL := fmt.Sprintf("SET DEFAULT ROLE %s TO %s", roleList, accountName(user, host))
+	logStatement(stmtSQL)
+
+	_, err := execContext(ctx, db, stmtSQL)
+	if err != nil {
+		return fmt.Errorf("Error setting default roles of %s: %s", accountName(user, host), err)
+	}
+
+	return nil
+}
+
+// normalizeRoleName spells a role on the default "%" host without the host,
+// so "r" and "r@%" name the same role.
+func normalizeRoleName(role string) string {
+	name, host := splitAccountID(role)
+	if host == "%" {
+		return name
+	}
+	return fmt.Sprintf("%s@%s", name, host)
+}
+
+func hashRoleName(v interface{}) int {
+	return schema.HashString(normalizeRoleName(v.(string)))
+}
@@ -0,0 +1,80 @@
+package mysql_provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccDefaultRoles_basic(t *testing.T) {
+	user := testAccDatabasePrefix + acctest.RandString(8)
+	role := testAccDatabasePrefix + acctest.RandString(8)
+	defaultRoleQuery := "SELECT 1 FROM mysql.default_roles WHERE USER = ? AND HOST = 'localhost' AND DEFAULT_ROLE_USER = ?"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccSkipUnlessMySQL(t, "8.0.0")
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRows(0, "SELECT 1 FROM mysql.user WHERE User IN (?, ?)", user, role),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "mysql_role" "test" {
+  name = %q
+}
+
+resource "mysql_user" "test" {
+  user               = %q
+  host               = "localhost"
+  plaintext_password = "Passw0rd!"
+}
+
+resource "mysql_role_grant" "test" {
+  role    = mysql_role.test.name
+  to_user = mysql_user.test.user
+  to_host = mysql_user.test.host
+}
+
+resource "mysql_default_roles" "test" {
+  user       = mysql_user.test.user
+  host       = mysql_user.test.host
+  roles      = [mysql_role.test.name]
+  depends_on = [mysql_role_grant.test]
+}
+`, role, user),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRows(1, defaultRoleQuery, user, role),
+					resource.TestCheckResourceAttr("mysql_default_roles.test", "id", user+"@localhost"),
+					resource.TestCheckResourceAttr("mysql_default_roles.test", "roles.#", "1"),
+				),
+			},
+			{
+				ResourceName:      "mysql_default_roles.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestNormalizeRoleName(t *testing.T) {
+	cases := map[string]string{
+		"reader":           "reader",
+		"reader@%":         "reader",
+		"reader@localhost": "reader@localhost",
+		"a@b@%":            "a@b",
+	}
+	for in, expected := range cases {
+		if got := normalizeRoleName(in); got != expected {
+			t.Errorf("normalizeRoleName(%q) = %q, want %q", in, got, expected)
+		}
+	}
+
+	if hashRoleName("reader") != hashRoleName("reader@%") {
+		t.Error("reader and reader@% hash differently")
+	}
+}
@@ -0,0 +1,219 @@
+package mysql_provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func ResourceEvent() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			// Lets the resource manage objects in the schemas the server
+			// maintains itself, e.g. mysql or sys.
+			"allow_system_schema": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			// The ON SCHEDULE clause as is, e.g. "EVERY 1 HOUR" or
+			// "AT CURRENT_TIMESTAMP + INTERVAL 1 DAY". The server doesn't keep
+			// it in a form that can be compared, so it isn't read back.
+			"schedule": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"body": {
+				Type:     schema.TypeString,
+				Required: true,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return normalizeWhitespace(old) == normalizeWhitespace(new)
+				},
+			},
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			// RFC 3339, empty until the event first ran.
+			"last_executed": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+		SchemaVersion:  0,
+		MigrateState:   nil,
+		StateUpgraders: nil,
+		Create:         CreateEvent,
+		Read:           ReadEvent,
+		Update:         UpdateEvent,
+		Delete:         DeleteEvent,
+		Exists:         nil,
+		CustomizeDiff: customdiff.All(
+			validateManagedObjectSchema,
+			validateSystemObjectSchema,
+		),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		DeprecationMessage: "",
+		Timeouts:           nil,
+		Description:        "",
+	}
+}
+
+func CreateEvent(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	database := d.Get("database").(string)
+	name := d.Get("name").(string)
+	if err := meta.(*MySQLConfiguration).checkManagedSchema(database); err != nil {
+		return err
+	}
+	if err := checkSystemSchema(d, database, "create an event in"); err != nil {
+		return err
+	}
+
+	warnEventSchedulerOff(ctx, db)
+
+	stmtSQL := fmt.Sprintf("CREATE EVENT %s.%s ON SCHEDULE %s %s DO %s",
+		quoteIdentifier(database), quoteIdentifier(name),
+		d.Get("schedule").(string), eventStatusClause(d), d.Get("body").(string))
+	logStatement(stmtSQL)
+
+	err := execDDL(ctx, db, stmtSQL)
+	if err != nil {
+		return fmt.Errorf("Error creating event %s.%s: %s", database, name, err)
+	}
+	d.SetId(fmt.Sprintf("%s.%s", database, name))
+
+	return ReadEvent(d, meta)
+}
+
+func ReadEvent(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	database, name := splitTableID(d.Id())
+
+	stmtSQL := "SELECT `EVENT_DEFINITION`, `STATUS`, `LAST_EXECUTED` FROM `information_schema`.`EVENTS` WHERE `EVENT_SCHEMA` = ? AND `EVENT_NAME` = ?"
+	logQuery(stmtSQL)
+
+	var body, status string
+	var lastExecuted nullDateTime
+	err := db.QueryRowContext(ctx, stmtSQL, database, name).Scan(&body, &status, &lastExecuted)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("Error reading event %s.%s: %s", database, name, err)
+	}
+
+	d.Set("database", database)
+	d.Set("name", name)
+	d.Set("body", body)
+	// SLAVESIDE_DISABLED events are disabled on replicas only.
+	d.Set("enabled", status != "DISABLED")
+	d.Set("last_executed", lastExecuted.String())
+
+	return nil
+}
+
+func UpdateEvent(d *schema.ResourceData, meta interface{}) error {
+	if !d.HasChange("schedule") && !d.HasChange("body") && !d.HasChange("enabled") {
+		return ReadEvent(d, meta)
+	}
+
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	database, name := splitTableID(d.Id())
+
+	stmtSQL := fmt.Sprintf("ALTER EVENT %s.%s", quoteIdentifier(database), quoteIdentifier(name))
+	if d.HasChange("schedule") {
+		stmtSQL += " ON SCHEDULE " + d.Get("schedule").(string)
+	}
+	if d.HasChange("enabled") {
+		stmtSQL += " " + eventStatusClause(d)
+	}
+	if d.HasChange("body") {
+		stmtSQL += " DO " + d.Get("body").(string)
+	}
+	logStatement(stmtSQL)
+
+	err := execDDL(ctx, db, stmtSQL)
+	if err != nil {
+		return fmt.Errorf("Error altering event %s.%s: %s", database, name, err)
+	}
+
+	return ReadEvent(d, meta)
+}
+
+func DeleteEvent(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	database, name := splitTableID(d.Id())
+	if err := meta.(*MySQLConfiguration).checkManagedSchema(database); err != nil {
+		return err
+	}
+	if err := checkSystemSchema(d, database, "drop an event in"); err != nil {
+		return err
+	}
+
+	stmtSQL := fmt.Sprintf("DROP EVENT IF EXISTS %s.%s", quoteIdentifier(database), quoteIdentifier(name))
+	logStatement(stmtSQL)
+
+	_, err := execContext(ctx, db, stmtSQL)
+	if err != nil {
+		return fmt.Errorf("Error dropping event %s.%s: %s", database, name, err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func eventStatusClause(d *schema.ResourceData) string {
+	if d.Get("enabled").(bool) {
+		return "ENABLE"
+	}
+	return "DISABLE"
+}
+
+// warnEventSchedulerOff logs a warning when events won't run because the
+// event scheduler is turned off. Creating the event succeeds regardless.
+func warnEventSchedulerOff(ctx context.Context, db *sql.DB) {
+	stmtSQL := "SELECT @@GLOBAL.event_scheduler"
+	logQuery(stmtSQL)
+
+	var scheduler string
+	err := db.QueryRowContext(ctx, stmtSQL).Scan(&scheduler)
+	if err != nil {
+		log.Printf("[WARN] Could not read event_scheduler: %s", err)
+		return
+	}
+	if !strings.EqualFold(scheduler, "ON") {
+		log.Printf("[WARN] event_scheduler is %s, events won't run until it is turned ON", scheduler)
+	}
+}
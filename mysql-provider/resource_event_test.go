@@ -0,0 +1,80 @@
+package mysql_provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccEvent_basic(t *testing.T) {
+	database := testAccDatabasePrefix + acctest.RandString(8)
+	statusQuery := "SELECT 1 FROM information_schema.EVENTS WHERE EVENT_SCHEMA = ? AND EVENT_NAME = 'purge' AND EVENT_TYPE = 'RECURRING' AND STATUS = ?"
+	config := func(enabled bool) string {
+		return fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name = %q
+}
+
+resource "mysql_event" "test" {
+  database = mysql_database.test.name
+  name     = "purge"
+  schedule = "EVERY 1 HOUR"
+  body     = "DO 1"
+  enabled  = %t
+}
+`, database, enabled)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRows(0, "SELECT 1 FROM information_schema.EVENTS WHERE EVENT_SCHEMA = ?", database),
+		Steps: []resource.TestStep{
+			{
+				Config: config(true),
+				Check:  testAccCheckRows(1, statusQuery, database, "ENABLED"),
+			},
+			{
+				Config: config(false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRows(1, statusQuery, database, "DISABLED"),
+					resource.TestCheckResourceAttr("mysql_event.test", "enabled", "false"),
+				),
+			},
+			{
+				Config: config(true),
+				Check:  testAccCheckRows(1, statusQuery, database, "ENABLED"),
+			},
+		},
+	})
+}
+
+func TestWarnEventSchedulerOff(t *testing.T) {
+	cases := map[string]bool{
+		"ON":       false,
+		"OFF":      true,
+		"DISABLED": true,
+	}
+	for scheduler, warns := range cases {
+		server := newFakeServer(t, func(query string) fakeResult {
+			if query == "SELECT @@GLOBAL.event_scheduler" {
+				return fakeRow("@@GLOBAL.event_scheduler", scheduler)
+			}
+			return fakeDefaultResult(query)
+		})
+		conf, err := testProviderConfigure(t, map[string]interface{}{"endpoint": server.addr()})
+		if err != nil {
+			t.Fatalf("providerConfigure returned %s", err)
+		}
+
+		output := testCaptureLog(t)
+		warnEventSchedulerOff(context.Background(), conf.connection())
+		if got := strings.Contains(output.String(), "events won't run"); got != warns {
+			t.Errorf("warnEventSchedulerOff with event_scheduler %s warned = %t, want %t", scheduler, got, warns)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package mysql_provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+const unknownSystemVariableErr = 1193
+const specificAccessDeniedErr = 1227
+
+var variableNameRegexp = regexp.MustCompile("^[a-zA-Z0-9_.]+$")
+var variableSizeRegexp = regexp.MustCompile("^[0-9]+[KMGTkmgt]$")
+
+func ResourceGlobalVariable() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(variableNameRegexp, "The variable name may only contain letters, digits, underscores and dots."),
+			},
+			"value": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+		},
+		SchemaVersion:  0,
+		MigrateState:   nil,
+		StateUpgraders: nil,
+		Create:         CreateGlobalVariable,
+		Read:           ReadGlobalVariable,
+		Update:         UpdateGlobalVariable,
+		Delete:         DeleteGlobalVariable,
+		Exists:         nil,
+		CustomizeDiff:  nil,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		DeprecationMessage: "",
+		Timeouts:           nil,
+		Description:        "",
+	}
+}
+
+func CreateGlobalVariable(d *schema.ResourceData, meta interface{}) error {
+	name := d.Get("name").(string)
+
+	err := setGlobalVariable(meta, name, quoteVariableValue(d.Get("value").(string)))
+	if err != nil {
+		return err
+	}
+	d.SetId(name)
+
+	return ReadGlobalVariable(d, meta)
+}
+
+func ReadGlobalVariable(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	name := d.Id()
+	value, err := readGlobalVariable(ctx, db, name)
+	if err != nil {
+		return err
+	}
+	if configured := d.Get("value").(string); equivalentVariableValues(configured, value) {
+		value = configured
+	}
+
+	d.Set("name", name)
+	d.Set("value", value)
+
+	return nil
+}
+
+func UpdateGlobalVariable(d *schema.ResourceData, meta interface{}) error {
+	if d.HasChange("value") {
+		err := setGlobalVariable(meta, d.Id(), quoteVariableValue(d.Get("value").(string)))
+		if err != nil {
+			return err
+		}
+	}
+
+	return ReadGlobalVariable(d, meta)
+}
+
+func DeleteGlobalVariable(d *schema.ResourceData, meta interface{}) error {
+	err := setGlobalVariable(meta, d.Id(), "DEFAULT")
+	if err != nil {
+		return err
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func setGlobalVariable(meta interface{}, name string, value string) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	if !variableNameRegexp.MatchString(name) {
+		return fmt.Errorf("Invalid global variable name %q", name)
+	}
+
+	stmtSQL := fmt.Sprintf("SET GLOBAL %s = %s", name, value)
+	logStatement(stmtSQL)
+
+	_, err := execContext(ctx, db, stmtSQL)
+	if err != nil {
+		return globalVariableError(name, err)
+	}
+
+	return nil
+}
+
+// readGlobalVariable returns the current value of the global variable, a NULL
+// value reads as empty.
+func readGlobalVariable(ctx context.Context, db *sql.DB, name string) (string, error) {
+	if !variableNameRegexp.MatchString(name) {
+		return "", fmt.Errorf("Invalid global variable name %q", name)
+	}
+	stmtSQL := "SELECT @@GLOBAL." + name
+	logQuery(stmtSQL)
+
+	var value sql.NullString
+	err := db.QueryRowContext(ctx, stmtSQL).Scan(&value)
+	if err != nil {
+		return "", globalVariableError(name, err)
+	}
+
+	return value.String, nil
+}
+
+// variableSizeSuffixes are the suffixes SET GLOBAL accepts on sizes.
+var variableSizeSuffixes = map[byte]float64{
+	'K': 1 << 10,
+	'M': 1 << 20,
+	'G': 1 << 30,
+	'T': 1 << 40,
+}
+
+// equivalentVariableValues reports whether a configured value is what the
+// server reads back as value, e.g. ON as 1, 64M as 67108864 or innodb as
+// InnoDB.
+func equivalentVariableValues(configured string, value string) bool {
+	if configured == value {
+		return true
+	}
+	if configuredNum, ok := numericVariableValue(configured); ok {
+		valueNum, ok := numericVariableValue(value)
+		return ok && configuredNum == valueNum
+	}
+	return strings.EqualFold(configured, value)
+}
+
+// numericVariableValue parses numbers, sizes with a K, M, G or T suffix and
+// the ON/OFF and TRUE/FALSE spellings of booleans.
+func numericVariableValue(value string) (float64, bool) {
+	switch strings.ToUpper(value) {
+	case "ON", "TRUE":
+		return 1, true
+	case "OFF", "FALSE":
+		return 0, true
+	case "":
+		return 0, false
+	}
+	multiplier := 1.0
+	if m, ok := variableSizeSuffixes[strings.ToUpper(value[len(value)-1:])[0]]; ok {
+		multiplier = m
+		value = value[:len(value)-1]
+	}
+	num, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false
+	}
+	return num * multiplier, true
+}
+
+// quoteVariableValue leaves numeric values bare, since numeric variables
+// reject quoted arguments, and quotes everything else as a string literal.
+// SET only takes size suffixes at startup, so sizes are sent in bytes.
+func quoteVariableValue(value string) string {
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	if variableSizeRegexp.MatchString(value) {
+		size, _ := numericVariableValue(value)
+		return strconv.FormatFloat(size, 'f', -1, 64)
+	}
+	return quoteString(value)
+}
+
+func globalVariableError(name string, err error) error {
+	if mysqlErr, ok := err.(*mysql.MySQLError); ok {
+		switch mysqlErr.Number {
+		case unknownSystemVariableErr:
+			return fmt.Errorf("Unknown global variable %s", name)
+		case specificAccessDeniedErr:
+			return fmt.Errorf("Setting global variable %s requires the SUPER or SYSTEM_VARIABLES_ADMIN privilege: %s", name, err)
+		}
+	}
+	return fmt.Errorf("Error accessing global variable %s: %s", name, err)
+}
@@ -0,0 +1,89 @@
+package mysql_provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccGlobalVariable_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGlobalVariableConfig("max_connections", "200"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRows(1, "SELECT 1 FROM DUAL WHERE @@GLOBAL.max_connections = 200"),
+					resource.TestCheckResourceAttr("mysql_global_variable.test", "value", "200"),
+				),
+			},
+			{
+				Config: testAccGlobalVariableConfig("max_connections", "250"),
+				Check:  testAccCheckRows(1, "SELECT 1 FROM DUAL WHERE @@GLOBAL.max_connections = 250"),
+			},
+			{
+				// The server reads sizes back in bytes, which mustn't show up as
+				// a diff.
+				Config: testAccGlobalVariableConfig("max_allowed_packet", "64M"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRows(1, "SELECT 1 FROM DUAL WHERE @@GLOBAL.max_allowed_packet = 67108864"),
+					resource.TestCheckResourceAttr("mysql_global_variable.test", "value", "64M"),
+				),
+			},
+		},
+	})
+}
+
+func testAccGlobalVariableConfig(name string, value string) string {
+	return fmt.Sprintf(`
+resource "mysql_global_variable" "test" {
+  name  = %q
+  value = %q
+}
+`, name, value)
+}
+
+func TestEquivalentVariableValues(t *testing.T) {
+	cases := []struct {
+		configured string
+		value      string
+		expected   bool
+	}{
+		{"1", "1", true},
+		{"ON", "1", true},
+		{"true", "1", true},
+		{"OFF", "0", true},
+		{"64M", "67108864", true},
+		{"1g", "1073741824", true},
+		{"innodb", "InnoDB", true},
+		{"0.5", "0.500000", true},
+		{"ON", "0", false},
+		{"64M", "64", false},
+		{"", "0", false},
+		{"READ-COMMITTED", "REPEATABLE-READ", false},
+	}
+	for _, c := range cases {
+		if got := equivalentVariableValues(c.configured, c.value); got != c.expected {
+			t.Errorf("equivalentVariableValues(%q, %q) = %t, want %t", c.configured, c.value, got, c.expected)
+		}
+	}
+}
+
+func TestQuoteVariableValue(t *testing.T) {
+	cases := map[string]string{
+		"100":            "100",
+		"0.25":           "0.25",
+		"16K":            "16384",
+		"2G":             "2147483648",
+		"ON":             "'ON'",
+		"READ-COMMITTED": "'READ-COMMITTED'",
+		"it's":           "'it''s'",
+	}
+	for in, expected := range cases {
+		if got := quoteVariableValue(in); got != expected {
+			t.Errorf("quoteVariableValue(%q) = %q, want %q", in, got, expected)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package mysql_provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func ResourceGlobalVariables() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"variables": {
+				Type:     schema.TypeMap,
+				Required: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+		SchemaVersion:      0,
+		MigrateState:       nil,
+		StateUpgraders:     nil,
+		Create:             CreateGlobalVariables,
+		Read:               ReadGlobalVariables,
+		Update:             UpdateGlobalVariables,
+		Delete:             DeleteGlobalVariables,
+		Exists:             nil,
+		CustomizeDiff:      nil,
+		Importer:           nil,
+		DeprecationMessage: "",
+		Timeouts:           nil,
+		Description:        "",
+	}
+}
+
+func CreateGlobalVariables(d *schema.ResourceData, meta interface{}) error {
+	for name, value := range d.Get("variables").(map[string]interface{}) {
+		err := setGlobalVariable(meta, name, quoteVariableValue(value.(string)))
+		if err != nil {
+			return err
+		}
+	}
+	d.SetId(resource.UniqueId())
+
+	return ReadGlobalVariables(d, meta)
+}
+
+// ReadGlobalVariables only reads back the variables the resource manages.
+func ReadGlobalVariables(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	variables := map[string]interface{}{}
+	for name, configured := range d.Get("variables").(map[string]interface{}) {
+		value, err := readGlobalVariable(ctx, db, name)
+		if err != nil {
+			return err
+		}
+		if equivalentVariableValues(configured.(string), value) {
+			value = configured.(string)
+		}
+		variables[name] = value
+	}
+
+	d.Set("variables", variables)
+
+	return nil
+}
+
+// UpdateGlobalVariables sets the added and changed variables and resets the
+// removed ones to their defaults.
+func UpdateGlobalVariables(d *schema.ResourceData, meta interface{}) error {
+	if d.HasChange("variables") {
+		o, n := d.GetChange("variables")
+		oldVariables := o.(map[string]interface{})
+		newVariables := n.(map[string]interface{})
+
+		for name := range oldVariables {
+			if _, ok := newVariables[name]; ok {
+				continue
+			}
+			err := setGlobalVariable(meta, name, "DEFAULT")
+			if err != nil {
+				return err
+			}
+		}
+		for name, value := range newVariables {
+			if oldValue, ok := oldVariables[name]; ok && oldValue == value {
+				continue
+			}
+			err := setGlobalVariable(meta, name, quoteVariableValue(value.(string)))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return ReadGlobalVariables(d, meta)
+}
+
+func DeleteGlobalVariables(d *schema.ResourceData, meta interface{}) error {
+	for name := range d.Get("variables").(map[string]interface{}) {
+		err := setGlobalVariable(meta, name, "DEFAULT")
+		if err != nil {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,83 @@
+package mysql_provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccGlobalVariables_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckRows(1, "SELECT 1 FROM DUAL WHERE @@GLOBAL.max_connections = 151"),
+			testAccCheckRows(1, "SELECT 1 FROM DUAL WHERE @@GLOBAL.wait_timeout = 28800"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "mysql_global_variables" "test" {
+  variables = {
+    max_connections = "200"
+    wait_timeout    = "600"
+  }
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRows(1, "SELECT 1 FROM DUAL WHERE @@GLOBAL.max_connections = 200"),
+					testAccCheckRows(1, "SELECT 1 FROM DUAL WHERE @@GLOBAL.wait_timeout = 600"),
+				),
+			},
+			{
+				Config: `
+resource "mysql_global_variables" "test" {
+  variables = {
+    max_connections = "200"
+  }
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRows(1, "SELECT 1 FROM DUAL WHERE @@GLOBAL.max_connections = 200"),
+					testAccCheckRows(1, "SELECT 1 FROM DUAL WHERE @@GLOBAL.wait_timeout = 28800"),
+					resource.TestCheckResourceAttr("mysql_global_variables.test", "variables.%", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestUpdateGlobalVariablesResetsRemoved(t *testing.T) {
+	server := newFakeServer(t, func(query string) fakeResult {
+		if strings.HasPrefix(query, "SELECT @@GLOBAL.max_connections") {
+			return fakeRow("@@GLOBAL.max_connections", "200")
+		}
+		return fakeDefaultResult(query)
+	})
+	conf, err := testProviderConfigure(t, map[string]interface{}{"endpoint": server.addr()})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+
+	state := map[string]string{
+		"variables.%":               "2",
+		"variables.max_connections": "200",
+		"variables.wait_timeout":    "600",
+	}
+	raw := map[string]interface{}{
+		"variables": map[string]interface{}{"max_connections": "200"},
+	}
+	d := testResourceDataDiff(t, ResourceGlobalVariables(), state, raw)
+	if err := UpdateGlobalVariables(d, conf); err != nil {
+		t.Fatalf("UpdateGlobalVariables returned %s", err)
+	}
+
+	queries := server.receivedQueries()
+	if !fakeQueriesContain(queries, "SET GLOBAL wait_timeout = DEFAULT") {
+		t.Errorf("UpdateGlobalVariables didn't reset the removed wait_timeout, ran %q", queries)
+	}
+	if fakeQueriesContain(queries, "SET GLOBAL max_connections") {
+		t.Errorf("UpdateGlobalVariables set the unchanged max_connections, ran %q", queries)
+	}
+}
@@ -0,0 +1,151 @@
+package mysql_provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+const unknownPluginErr = 1305
+
+func ResourcePlugin() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			// Defaults to "<name>.so".
+			"soname": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			// Set when the plugin was already installed on create, it is left
+			// installed on destroy then.
+			"adopted": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+		},
+		SchemaVersion:  0,
+		MigrateState:   nil,
+		StateUpgraders: nil,
+		Create:         CreatePlugin,
+		Read:           ReadPlugin,
+		Update:         nil,
+		Delete:         DeletePlugin,
+		Exists:         nil,
+		CustomizeDiff:  nil,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		DeprecationMessage: "",
+		Timeouts:           nil,
+		Description:        "",
+	}
+}
+
+func CreatePlugin(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	name := d.Get("name").(string)
+	soname := d.Get("soname").(string)
+	if soname == "" {
+		soname = name + ".so"
+	}
+
+	installed, err := pluginLibrary(ctx, db, name)
+	if err != nil {
+		return err
+	}
+	if installed != nil {
+		// Plugins are server-wide, adopt one that is already there.
+		log.Printf("[WARN] Plugin %s is already installed, adopting it", name)
+		d.Set("adopted", true)
+	} else {
+		stmtSQL := fmt.Sprintf("INSTALL PLUGIN %s SONAME %s", quoteIdentifier(name), quoteString(soname))
+		logStatement(stmtSQL)
+
+		_, err = execContext(ctx, db, stmtSQL)
+		if err != nil {
+			return fmt.Errorf("Error installing plugin %s: %s", name, err)
+		}
+	}
+	d.SetId(name)
+
+	return ReadPlugin(d, meta)
+}
+
+func ReadPlugin(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	library, err := pluginLibrary(ctx, db, d.Id())
+	if err != nil {
+		return err
+	}
+	if library == nil {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("name", d.Id())
+	d.Set("soname", library.String)
+
+	return nil
+}
+
+func DeletePlugin(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	name := d.Id()
+	if d.Get("adopted").(bool) {
+		log.Printf("[WARN] Plugin %s was installed before it was adopted, leaving it installed", name)
+		d.SetId("")
+		return nil
+	}
+
+	stmtSQL := "UNINSTALL PLUGIN " + quoteIdentifier(name)
+	logStatement(stmtSQL)
+
+	_, err := execContext(ctx, db, stmtSQL)
+	if err != nil {
+		// The plugin was already uninstalled out-of-band, nothing left to do.
+		if mysqlErr, ok := err.(*mysql.MySQLError); !ok || mysqlErr.Number != unknownPluginErr {
+			return fmt.Errorf("Error uninstalling plugin %s: %s", name, err)
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// pluginLibrary returns the library the named plugin was loaded from, which
+// is NULL for plugins built into the server, or nil when it isn't installed.
+func pluginLibrary(ctx context.Context, db *sql.DB, name string) (*sql.NullString, error) {
+	stmtSQL := "SELECT `PLUGIN_LIBRARY` FROM `information_schema`.`PLUGINS` WHERE `PLUGIN_NAME` = ?"
+	logQuery(stmtSQL)
+
+	var library sql.NullString
+	err := db.QueryRowContext(ctx, stmtSQL, name).Scan(&library)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Error reading plugin %s: %s", name, err)
+	}
+
+	return &library, nil
+}
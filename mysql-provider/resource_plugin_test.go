@@ -0,0 +1,91 @@
+package mysql_provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestAccPlugin_adopted(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		// Built into the server, so it must be adopted and left in place.
+		CheckDestroy: testAccCheckRows(1, "SELECT 1 FROM `information_schema`.`PLUGINS` WHERE `PLUGIN_NAME` = 'mysql_native_password'"),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "mysql_plugin" "test" {
+  name = "mysql_native_password"
+}
+`,
+				Check: resource.TestCheckResourceAttr("mysql_plugin.test", "adopted", "true"),
+			},
+		},
+	})
+}
+
+func TestCreatePluginAdopts(t *testing.T) {
+	server := newFakeServer(t, func(query string) fakeResult {
+		if strings.Contains(query, "`information_schema`.`PLUGINS`") {
+			return fakeRow("PLUGIN_LIBRARY", nil)
+		}
+		return fakeDefaultResult(query)
+	})
+	conf, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint":           server.addr(),
+		"interpolate_params": true,
+	})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+
+	d := schema.TestResourceDataRaw(t, ResourcePlugin().Schema, map[string]interface{}{"name": "mysql_native_password"})
+	if err := CreatePlugin(d, conf); err != nil {
+		t.Fatalf("CreatePlugin returned %s", err)
+	}
+	if !d.Get("adopted").(bool) {
+		t.Error("CreatePlugin of an installed plugin didn't set adopted")
+	}
+	if err := DeletePlugin(d, conf); err != nil {
+		t.Fatalf("DeletePlugin returned %s", err)
+	}
+	for _, stmt := range []string{"INSTALL PLUGIN", "UNINSTALL PLUGIN"} {
+		if fakeQueriesContain(server.receivedQueries(), stmt) {
+			t.Errorf("adopting a plugin issued %s, got %q", stmt, server.receivedQueries())
+		}
+	}
+}
+
+func TestDeletePlugin(t *testing.T) {
+	server := newFakeServer(t, func(query string) fakeResult {
+		if strings.HasPrefix(query, "UNINSTALL PLUGIN `gone`") {
+			return fakeError(unknownPluginErr, "PLUGIN gone does not exist")
+		}
+		if strings.HasPrefix(query, "UNINSTALL PLUGIN `locked`") {
+			return fakeError(specificAccessDeniedErr, "Access denied; you need (at least one of) the DELETE privilege(s) for this operation")
+		}
+		return fakeDefaultResult(query)
+	})
+	conf, err := testProviderConfigure(t, map[string]interface{}{"endpoint": server.addr()})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+
+	d := ResourcePlugin().Data(nil)
+	d.SetId("gone")
+	if err := DeletePlugin(d, conf); err != nil {
+		t.Errorf("DeletePlugin of an uninstalled plugin returned %s", err)
+	}
+	if d.Id() != "" {
+		t.Errorf("DeletePlugin kept the ID %q", d.Id())
+	}
+
+	d = ResourcePlugin().Data(nil)
+	d.SetId("locked")
+	if err := DeletePlugin(d, conf); err == nil {
+		t.Error("DeletePlugin ignored a failed UNINSTALL PLUGIN")
+	}
+}
@@ -0,0 +1,231 @@
+package mysql_provider
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// procedureNameRegexp matches the start of a CREATE PROCEDURE statement up to
+// the procedure's name, which may be qualified with its database.
+var procedureNameRegexp = regexp.MustCompile("(?is)^CREATE\\s+(?:DEFINER\\s*=\\s*\\S+\\s+)?PROCEDURE\\s+(?:IF\\s+NOT\\s+EXISTS\\s+)?" +
+	"(?:(`(?:[^`]|``)+`|[^\\s`.(]+)\\s*\\.\\s*)?(`(?:[^`]|``)+`|[^\\s`.(]+)")
+
+func ResourceProcedure() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			// Lets the resource manage objects in the schemas the server
+			// maintains itself, e.g. mysql or sys.
+			"allow_system_schema": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			// The complete CREATE PROCEDURE statement, run in the database.
+			// DELIMITER lines as used with the mysql client are accepted.
+			"body": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+		},
+		SchemaVersion:  0,
+		MigrateState:   nil,
+		StateUpgraders: nil,
+		Create:         CreateProcedure,
+		Read:           ReadProcedure,
+		Update:         UpdateProcedure,
+		Delete:         DeleteProcedure,
+		Exists:         nil,
+		CustomizeDiff: customdiff.All(
+			validateManagedObjectSchema,
+			validateSystemObjectSchema,
+			validateProcedureBody,
+		),
+		Importer:           nil,
+		DeprecationMessage: "",
+		Timeouts:           nil,
+		Description:        "",
+	}
+}
+
+func CreateProcedure(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	database := d.Get("database").(string)
+	name := d.Get("name").(string)
+	if err := meta.(*MySQLConfiguration).checkManagedSchema(database); err != nil {
+		return err
+	}
+	if err := checkSystemSchema(d, database, "create a procedure in"); err != nil {
+		return err
+	}
+	if err := checkProcedureBody(database, name, d.Get("body").(string)); err != nil {
+		return err
+	}
+
+	// The body may refer to the procedure unqualified, so it has to run
+	// with the database selected on the same connection. The connection is
+	// discarded afterwards rather than going back to the pool with that
+	// default database.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	defer conn.Raw(func(interface{}) error {
+		return driver.ErrBadConn
+	})
+
+	stmtSQL := "USE " + quoteIdentifier(database)
+	logStatement(stmtSQL)
+	_, err = execContext(ctx, conn, stmtSQL)
+	if err != nil {
+		return fmt.Errorf("Error creating procedure %s.%s: %s", database, name, err)
+	}
+
+	stmtSQL = stripDelimiters(d.Get("body").(string))
+	logStatement(stmtSQL)
+	_, err = execContext(ctx, conn, stmtSQL)
+	if err != nil {
+		return fmt.Errorf("Error creating procedure %s.%s: %s", database, name, err)
+	}
+	d.SetId(fmt.Sprintf("%s.%s", database, name))
+
+	return ReadProcedure(d, meta)
+}
+
+func ReadProcedure(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	database, name := splitTableID(d.Id())
+
+	stmtSQL := "SELECT `ROUTINE_NAME` FROM `information_schema`.`ROUTINES` WHERE `ROUTINE_SCHEMA` = ? AND `ROUTINE_NAME` = ? AND `ROUTINE_TYPE` = 'PROCEDURE'"
+	logQuery(stmtSQL)
+
+	var _name string
+	err := db.QueryRowContext(ctx, stmtSQL, database, name).Scan(&_name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("Error reading procedure %s.%s: %s", database, name, err)
+	}
+
+	return nil
+}
+
+// UpdateProcedure only stores allow_system_schema, every other attribute forces a
+// new procedure.
+func UpdateProcedure(d *schema.ResourceData, meta interface{}) error {
+	return ReadProcedure(d, meta)
+}
+
+func DeleteProcedure(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	database, name := splitTableID(d.Id())
+	if err := meta.(*MySQLConfiguration).checkManagedSchema(database); err != nil {
+		return err
+	}
+	if err := checkSystemSchema(d, database, "drop a procedure in"); err != nil {
+		return err
+	}
+
+	stmtSQL := fmt.Sprintf("DROP PROCEDURE IF EXISTS %s.%s", quoteIdentifier(database), quoteIdentifier(name))
+	logStatement(stmtSQL)
+
+	_, err := execContext(ctx, db, stmtSQL)
+	if err != nil {
+		return fmt.Errorf("Error dropping procedure %s.%s: %s", database, name, err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// validateProcedureBody rejects plans whose body creates a different procedure
+// than the one the resource is named after, which would be created and then
+// never found again.
+func validateProcedureBody(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("database") || !d.NewValueKnown("name") || !d.NewValueKnown("body") {
+		return nil
+	}
+	return checkProcedureBody(d.Get("database").(string), d.Get("name").(string), d.Get("body").(string))
+}
+
+// checkProcedureBody returns an error unless body is the CREATE PROCEDURE
+// statement of the procedure name in database. Routine names are case
+// insensitive, database names are compared as is.
+func checkProcedureBody(database, name, body string) error {
+	match := procedureNameRegexp.FindStringSubmatch(stripDelimiters(body))
+	if match == nil {
+		return fmt.Errorf("body of procedure %s.%s is not a CREATE PROCEDURE statement", database, name)
+	}
+	bodyDatabase, bodyName := unquoteIdentifier(match[1]), unquoteIdentifier(match[2])
+	if !strings.EqualFold(bodyName, name) || (bodyDatabase != "" && bodyDatabase != database) {
+		created := bodyName
+		if bodyDatabase != "" {
+			created = bodyDatabase + "." + bodyName
+		}
+		return fmt.Errorf("body of procedure %s.%s creates procedure %s instead", database, name, created)
+	}
+	return nil
+}
+
+// unquoteIdentifier reverses quoteIdentifier, leaving unquoted identifiers
+// alone.
+func unquoteIdentifier(ident string) string {
+	if len(ident) < 2 || ident[0] != '`' || ident[len(ident)-1] != '`' {
+		return ident
+	}
+	return strings.Replace(ident[1:len(ident)-1], "``", "`", -1)
+}
+
+// stripDelimiters turns a script written for the mysql client into the single
+// statement the server expects: DELIMITER lines are dropped along with the
+// custom delimiter ending the statement. The semicolons inside BEGIN ... END
+// are left alone, the server parses the compound statement as a whole so
+// multiStatements isn't needed.
+func stripDelimiters(body string) string {
+	// Scripts usually switch back with "DELIMITER ;" after the statement, so
+	// every delimiter that was in use is trimmed, not just the last one.
+	delimiters := []string{";"}
+	var lines []string
+	for _, line := range strings.Split(body, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && strings.EqualFold(fields[0], "DELIMITER") {
+			delimiters = append(delimiters, fields[1])
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	stmt := strings.TrimSpace(strings.Join(lines, "\n"))
+	for i := len(delimiters) - 1; i >= 0; i-- {
+		stmt = strings.TrimSpace(strings.TrimSuffix(stmt, delimiters[i]))
+	}
+	return stmt
+}
@@ -0,0 +1,105 @@
+package mysql_provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccProcedure_basic(t *testing.T) {
+	database := testAccDatabasePrefix + acctest.RandString(8)
+	procedureQuery := "SELECT 1 FROM information_schema.ROUTINES WHERE ROUTINE_SCHEMA = ? AND ROUTINE_NAME = 'add_user' AND ROUTINE_TYPE = 'PROCEDURE'"
+	config := func(body string) string {
+		return fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name = %q
+}
+
+resource "mysql_procedure" "test" {
+  database = mysql_database.test.name
+  name     = "add_user"
+  body     = <<-EOT
+%sEOT
+}
+`, database, body)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRows(0, procedureQuery, database),
+		Steps: []resource.TestStep{
+			{
+				Config:      config("CREATE PROCEDURE other() SELECT 1\n"),
+				ExpectError: regexp.MustCompile("creates procedure other instead"),
+			},
+			{
+				Config: config(`DELIMITER //
+CREATE PROCEDURE add_user(IN n INT)
+BEGIN
+  SET @users = IFNULL(@users, 0) + n;
+  SELECT @users;
+END //
+DELIMITER ;
+`),
+				Check: testAccCheckRows(1, procedureQuery, database),
+			},
+		},
+	})
+}
+
+func TestCheckProcedureBody(t *testing.T) {
+	cases := []struct {
+		body  string
+		valid bool
+	}{
+		{"CREATE PROCEDURE add_user() SELECT 1", true},
+		{"create procedure ADD_USER() SELECT 1", true},
+		{"CREATE PROCEDURE `add_user` () SELECT 1", true},
+		{"CREATE PROCEDURE app.add_user() SELECT 1", true},
+		{"CREATE PROCEDURE `app` . `add_user`() SELECT 1", true},
+		{"CREATE DEFINER=`admin`@`%` PROCEDURE IF NOT EXISTS add_user() SELECT 1", true},
+		{"DELIMITER //\nCREATE PROCEDURE add_user()\nBEGIN\n  SELECT 1;\nEND //\nDELIMITER ;", true},
+		{"CREATE PROCEDURE add_users() SELECT 1", false},
+		{"CREATE PROCEDURE other.add_user() SELECT 1", false},
+		{"CREATE FUNCTION add_user() RETURNS INT RETURN 1", false},
+		{"SELECT 1", false},
+	}
+	for _, c := range cases {
+		err := checkProcedureBody("app", "add_user", c.body)
+		if (err == nil) != c.valid {
+			t.Errorf("checkProcedureBody(%q) = %v, want valid %t", c.body, err, c.valid)
+		}
+	}
+}
+
+func TestUnquoteIdentifier(t *testing.T) {
+	cases := map[string]string{
+		"name":                 "name",
+		"`name`":               "name",
+		"`na``me`":             "na`me",
+		quoteIdentifier("a`b"): "a`b",
+	}
+	for in, expected := range cases {
+		if got := unquoteIdentifier(in); got != expected {
+			t.Errorf("unquoteIdentifier(%q) = %q, want %q", in, got, expected)
+		}
+	}
+}
+
+func TestStripDelimiters(t *testing.T) {
+	cases := map[string]string{
+		"CREATE PROCEDURE p() SELECT 1;": "CREATE PROCEDURE p() SELECT 1",
+		"DELIMITER //\nCREATE PROCEDURE p()\nBEGIN\n  SELECT 1;\n  SELECT 2;\nEND //\nDELIMITER ;\n": "CREATE PROCEDURE p()\nBEGIN\n  SELECT 1;\n  SELECT 2;\nEND",
+		"delimiter $$\nCREATE PROCEDURE p() BEGIN SELECT 1; END$$":                                   "CREATE PROCEDURE p() BEGIN SELECT 1; END",
+		"CREATE PROCEDURE p() BEGIN SELECT 1; END":                                                   "CREATE PROCEDURE p() BEGIN SELECT 1; END",
+	}
+	for in, expected := range cases {
+		if got := stripDelimiters(in); got != expected {
+			t.Errorf("stripDelimiters(%q) = %q, want %q", in, got, expected)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package mysql_provider
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func ResourceRole() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"host": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "%",
+			},
+		},
+		SchemaVersion:  0,
+		MigrateState:   nil,
+		StateUpgraders: nil,
+		Create:         CreateRole,
+		Read:           ReadRole,
+		Update:         nil,
+		Delete:         DeleteRole,
+		Exists:         nil,
+		CustomizeDiff:  nil,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		DeprecationMessage: "",
+		Timeouts:           nil,
+		Description:        "",
+	}
+}
+
+func CreateRole(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	if err := requireMySQL8(meta.(*MySQLConfiguration), "mysql_role"); err != nil {
+		return err
+	}
+
+	name := d.Get("name").(string)
+	host := d.Get("host").(string)
+
+	stmtSQL := "CREATE ROLE " + accountName(name, host)
+	logStatement(stmtSQL)
+
+	_, err := execContext(ctx, db, stmtSQL)
+	if err != nil {
+		return fmt.Errorf("Error creating role %s: %s", accountName(name, host), err)
+	}
+	d.SetId(fmt.Sprintf("%s@%s", name, host))
+
+	return ReadRole(d, meta)
+}
+
+func ReadRole(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	name, host := splitAccountID(d.Id())
+
+	// Roles are stored as locked accounts without any credentials.
+	stmtSQL := "SELECT `User` FROM `mysql`.`user` WHERE `User` = ? AND `Host` = ? AND `account_locked` = 'Y' AND `authentication_string` = ''"
+	logQuery(stmtSQL)
+
+	var _user string
+	err := db.QueryRowContext(ctx, stmtSQL, name, host).Scan(&_user)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("Error reading role %s: %s", accountName(name, host), err)
+	}
+
+	d.Set("name", name)
+	d.Set("host", host)
+
+	return nil
+}
+
+func DeleteRole(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	name, host := splitAccountID(d.Id())
+
+	stmtSQL := "DROP ROLE " + accountName(name, host)
+	logStatement(stmtSQL)
+
+	_, err := execContext(ctx, db, stmtSQL)
+	if err != nil {
+		return fmt.Errorf("Error dropping role %s: %s", accountName(name, host), err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// splitAccountID splits a "<user>@<host>" resource ID. The host defaults to
+// "%" when the ID doesn't carry one, e.g. on import of a plain role name.
+func splitAccountID(id string) (string, string) {
+	i := strings.LastIndex(id, "@")
+	if i == -1 {
+		return id, "%"
+	}
+	return id[:i], id[i+1:]
+}
@@ -0,0 +1,194 @@
+package mysql_provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+const nonexistingGrantErr = 1141
+
+var grantQuoteReplacer = strings.NewReplacer("`", "", "'", "")
+
+func ResourceRoleGrant() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			// The role is given as "<name>" or "<name>@<host>".
+			"role": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"to_user": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			// Defaults to the provider's default_user_host.
+			"to_host": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Computed: true,
+			},
+		},
+		SchemaVersion:      0,
+		MigrateState:       nil,
+		StateUpgraders:     nil,
+		Create:             CreateRoleGrant,
+		Read:               ReadRoleGrant,
+		Update:             nil,
+		Delete:             DeleteRoleGrant,
+		Exists:             nil,
+		CustomizeDiff:      nil,
+		Importer:           nil,
+		DeprecationMessage: "",
+		Timeouts:           nil,
+		Description:        "",
+	}
+}
+
+func CreateRoleGrant(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	if err := requireMySQL8(meta.(*MySQLConfiguration), "mysql_role_grant"); err != nil {
+		return err
+	}
+
+	applyDefaultUserHost(d, "to_host", meta)
+	role := d.Get("role").(string)
+	toUser := d.Get("to_user").(string)
+	toHost := d.Get("to_host").(string)
+
+	stmtSQL := fmt.Sprintf("GRANT %s TO %s", accountName(splitAccountID(role)), accountName(toUser, toHost))
+	logStatement(stmtSQL)
+
+	_, err := execContext(ctx, db, stmtSQL)
+	if err != nil {
+		return fmt.Errorf("Error granting role %s to %s: %s", role, accountName(toUser, toHost), err)
+	}
+	d.SetId(fmt.Sprintf("%s@%s:%s", toUser, toHost, role))
+	if err := flushPrivileges(ctx, meta.(*MySQLConfiguration), db); err != nil {
+		return err
+	}
+
+	return ReadRoleGrant(d, meta)
+}
+
+func ReadRoleGrant(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	role := d.Get("role").(string)
+	toUser := d.Get("to_user").(string)
+	toHost := d.Get("to_host").(string)
+
+	roles, err := grantedRoles(ctx, db, toUser, toHost)
+	if err != nil {
+		return err
+	}
+
+	roleName, roleHost := splitAccountID(role)
+	for _, granted := range roles {
+		if granted == fmt.Sprintf("%s@%s", roleName, roleHost) {
+			return nil
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func DeleteRoleGrant(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	role := d.Get("role").(string)
+	toUser := d.Get("to_user").(string)
+	toHost := d.Get("to_host").(string)
+
+	// Revoke from what is granted now rather than from the state, the grant
+	// may already be gone, e.g. revoked out-of-band.
+	roles, err := grantedRoles(ctx, db, toUser, toHost)
+	if err != nil {
+		return err
+	}
+	roleName, roleHost := splitAccountID(role)
+	granted := false
+	for _, r := range roles {
+		if r == fmt.Sprintf("%s@%s", roleName, roleHost) {
+			granted = true
+			break
+		}
+	}
+	if !granted {
+		log.Printf("[WARN] Role %s is no longer granted to %s, nothing to revoke", role, accountName(toUser, toHost))
+		d.SetId("")
+		return nil
+	}
+
+	stmtSQL := fmt.Sprintf("REVOKE %s FROM %s", accountName(roleName, roleHost), accountName(toUser, toHost))
+	logStatement(stmtSQL)
+
+	_, err = execContext(ctx, db, stmtSQL)
+	if err != nil {
+		return fmt.Errorf("Error revoking role %s from %s: %s", role, accountName(toUser, toHost), err)
+	}
+	if err := flushPrivileges(ctx, meta.(*MySQLConfiguration), db); err != nil {
+		return err
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// grantedRoles parses SHOW GRANTS for the role grants of an account, i.e. the
+// "GRANT `role`@`%` TO `user`@`host`" lines without an ON clause, and returns
+// them as "<name>@<host>". An unknown account has no roles.
+func grantedRoles(ctx context.Context, db *sql.DB, user string, host string) ([]string, error) {
+	stmtSQL := "SHOW GRANTS FOR " + accountName(user, host)
+	logQuery(stmtSQL)
+
+	rows, err := db.QueryContext(ctx, stmtSQL)
+	if err != nil {
+		if isUnknownGrantError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Error reading grants of %s: %s", accountName(user, host), err)
+	}
+	defer rows.Close()
+
+	var roles []string
+	for rows.Next() {
+		var grant string
+		if err := rows.Scan(&grant); err != nil {
+			return nil, fmt.Errorf("Error reading grants of %s: %s", accountName(user, host), err)
+		}
+		if !strings.HasPrefix(grant, "GRANT ") || strings.Contains(grant, " ON ") {
+			continue
+		}
+		end := strings.Index(grant, " TO ")
+		if end == -1 {
+			continue
+		}
+		for _, granted := range strings.Split(grant[len("GRANT "):end], ",") {
+			roles = append(roles, grantQuoteReplacer.Replace(strings.TrimSpace(granted)))
+		}
+	}
+
+	return roles, rows.Err()
+}
+
+func isUnknownGrantError(err error) bool {
+	mysqlErr, ok := err.(*mysql.MySQLError)
+	return ok && mysqlErr.Number == nonexistingGrantErr
+}
@@ -0,0 +1,133 @@
+package mysql_provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestAccRoleGrant_basic(t *testing.T) {
+	user := testAccDatabasePrefix + acctest.RandString(8)
+	role := testAccDatabasePrefix + acctest.RandString(8)
+	edgeQuery := "SELECT 1 FROM mysql.role_edges WHERE FROM_USER = ? AND TO_USER = ? AND TO_HOST = 'localhost'"
+	accounts := fmt.Sprintf(`
+resource "mysql_role" "test" {
+  name = %q
+}
+
+resource "mysql_user" "test" {
+  user               = %q
+  host               = "localhost"
+  plaintext_password = "Passw0rd!"
+}
+`, role, user)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccSkipUnlessMySQL(t, "8.0.0")
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRows(0, edgeQuery, role, user),
+		Steps: []resource.TestStep{
+			{
+				Config: accounts + `
+resource "mysql_role_grant" "test" {
+  role    = mysql_role.test.name
+  to_user = mysql_user.test.user
+  to_host = mysql_user.test.host
+}
+`,
+				Check: testAccCheckRows(1, edgeQuery, role, user),
+			},
+			{
+				Config: accounts,
+				Check:  testAccCheckRows(0, edgeQuery, role, user),
+			},
+		},
+	})
+}
+
+func TestGrantedRoles(t *testing.T) {
+	server := newFakeServer(t, func(query string) fakeResult {
+		switch query {
+		case "SHOW GRANTS FOR 'app'@'%'":
+			return fakeResult{
+				columns: []fakeColumn{{"Grants for app@%", fakeTypeVarString}},
+				rows: [][]interface{}{
+					{"GRANT USAGE ON *.* TO `app`@`%`"},
+					{"GRANT `reader`@`%`,`writer`@`localhost` TO `app`@`%`"},
+				},
+			}
+		case "SHOW GRANTS FOR 'gone'@'%'":
+			return fakeError(nonexistingGrantErr, "There is no such grant defined for user 'gone' on host '%'")
+		}
+		return fakeDefaultResult(query)
+	})
+	conf, err := testProviderConfigure(t, map[string]interface{}{"endpoint": server.addr()})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+
+	roles, err := grantedRoles(context.Background(), conf.connection(), "app", "%")
+	if err != nil {
+		t.Fatalf("grantedRoles returned %s", err)
+	}
+	if expected := []string{"reader@%", "writer@localhost"}; !reflect.DeepEqual(roles, expected) {
+		t.Errorf("grantedRoles = %q, want %q", roles, expected)
+	}
+
+	roles, err = grantedRoles(context.Background(), conf.connection(), "gone", "%")
+	if err != nil || len(roles) != 0 {
+		t.Errorf("grantedRoles of an unknown account = %q, %v, want no roles", roles, err)
+	}
+}
+
+func TestDeleteRoleGrant(t *testing.T) {
+	var revoked int32
+	server := newFakeServer(t, func(query string) fakeResult {
+		if query == "SHOW GRANTS FOR 'app'@'%'" {
+			rows := [][]interface{}{{"GRANT USAGE ON *.* TO `app`@`%`"}}
+			if atomic.LoadInt32(&revoked) == 0 {
+				rows = append(rows, []interface{}{"GRANT `reader`@`%` TO `app`@`%`"})
+			}
+			return fakeResult{columns: []fakeColumn{{"Grants for app@%", fakeTypeVarString}}, rows: rows}
+		}
+		return fakeDefaultResult(query)
+	})
+	conf, err := testProviderConfigure(t, map[string]interface{}{"endpoint": server.addr()})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+	raw := map[string]interface{}{"role": "reader", "to_user": "app", "to_host": "%"}
+
+	d := schema.TestResourceDataRaw(t, ResourceRoleGrant().Schema, raw)
+	d.SetId("reader@%:app@%")
+	if err := DeleteRoleGrant(d, conf); err != nil {
+		t.Fatalf("DeleteRoleGrant returned %s", err)
+	}
+	if !fakeQueriesContain(server.receivedQueries(), "REVOKE 'reader'@'%' FROM 'app'@'%'") {
+		t.Errorf("DeleteRoleGrant didn't revoke the role, ran %q", server.receivedQueries())
+	}
+
+	// Revoked out-of-band, there is nothing left to revoke.
+	atomic.StoreInt32(&revoked, 1)
+	before := len(server.receivedQueries())
+	d = schema.TestResourceDataRaw(t, ResourceRoleGrant().Schema, raw)
+	d.SetId("reader@%:app@%")
+	if err := DeleteRoleGrant(d, conf); err != nil {
+		t.Fatalf("DeleteRoleGrant of a revoked role returned %s", err)
+	}
+	if fakeQueriesContain(server.receivedQueries()[before:], "REVOKE") {
+		t.Errorf("DeleteRoleGrant revoked a role that isn't granted, ran %q", server.receivedQueries()[before:])
+	}
+	if d.Id() != "" {
+		t.Errorf("DeleteRoleGrant kept the ID %q", d.Id())
+	}
+}
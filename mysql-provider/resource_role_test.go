@@ -0,0 +1,41 @@
+package mysql_provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccRole_basic(t *testing.T) {
+	role := testAccDatabasePrefix + acctest.RandString(8)
+	roleQuery := "SELECT 1 FROM mysql.user WHERE User = ? AND Host = '%'"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccSkipUnlessMySQL(t, "8.0.0")
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRows(0, roleQuery, role),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "mysql_role" "test" {
+  name = %q
+}
+`, role),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRows(1, roleQuery, role),
+					resource.TestCheckResourceAttr("mysql_role.test", "id", role+"@%"),
+				),
+			},
+			{
+				ResourceName:      "mysql_role.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
@@ -0,0 +1,105 @@
+package mysql_provider
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func ResourceSQL() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"create_sql": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"update_sql": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"delete_sql": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+		SchemaVersion:      0,
+		MigrateState:       nil,
+		StateUpgraders:     nil,
+		Create:             CreateSQL,
+		Read:               ReadSQL,
+		Update:             UpdateSQL,
+		Delete:             DeleteSQL,
+		Exists:             nil,
+		CustomizeDiff:      nil,
+		Importer:           nil,
+		DeprecationMessage: "",
+		Timeouts:           nil,
+		Description:        "",
+	}
+}
+
+func CreateSQL(d *schema.ResourceData, meta interface{}) error {
+	err := execSQL(meta, d.Get("create_sql").(string))
+	if err != nil {
+		return err
+	}
+
+	name := d.Get("name").(string)
+	if name == "" {
+		name = resource.UniqueId()
+	}
+	d.SetId(name)
+
+	return ReadSQL(d, meta)
+}
+
+// ReadSQL has nothing to refresh, the statements are opaque to the provider.
+func ReadSQL(d *schema.ResourceData, meta interface{}) error {
+	return nil
+}
+
+func UpdateSQL(d *schema.ResourceData, meta interface{}) error {
+	if d.HasChange("update_sql") {
+		err := execSQL(meta, d.Get("update_sql").(string))
+		if err != nil {
+			return err
+		}
+	}
+
+	return ReadSQL(d, meta)
+}
+
+func DeleteSQL(d *schema.ResourceData, meta interface{}) error {
+	err := execSQL(meta, d.Get("delete_sql").(string))
+	if err != nil {
+		return err
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func execSQL(meta interface{}, stmtSQL string) error {
+	if stmtSQL == "" {
+		return nil
+	}
+
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+	logStatement(stmtSQL)
+
+	_, err := execContext(ctx, db, stmtSQL)
+	if err != nil {
+		return fmt.Errorf("Error executing statement: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package mysql_provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccSQL_basic(t *testing.T) {
+	database := testAccDatabasePrefix + acctest.RandString(8)
+	tableQuery := "SELECT 1 FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_NAME = 'items'"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRows(0, tableQuery, database),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name = %q
+}
+
+resource "mysql_sql" "test" {
+  name       = "items"
+  create_sql = "CREATE TABLE ${mysql_database.test.name}.items (id INT PRIMARY KEY)"
+  delete_sql = "DROP TABLE ${mysql_database.test.name}.items"
+}
+`, database),
+				Check: testAccCheckRows(1, tableQuery, database),
+			},
+		},
+	})
+}
@@ -0,0 +1,343 @@
+package mysql_provider
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// integerDisplayWidthRegexp matches the display width MySQL before 8.0.19
+// reports for integer types, e.g. the "(11)" of "int(11)".
+var integerDisplayWidthRegexp = regexp.MustCompile(`^(tinyint|smallint|mediumint|int|bigint)\(\d+\)`)
+
+// columnTypeAliases maps the synonyms MySQL accepts for a type to the type it
+// reports in information_schema, BOOL for example is stored as TINYINT(1).
+var columnTypeAliases = map[string]string{
+	"integer": "int",
+	"bool":    "tinyint(1)",
+	"boolean": "tinyint(1)",
+	"dec":     "decimal",
+	"numeric": "decimal",
+	"fixed":   "decimal",
+}
+
+// columnTypeNameRegexp matches the name a column type starts with.
+var columnTypeNameRegexp = regexp.MustCompile(`^[a-z]+`)
+
+// decimalPrecisionRegexp matches a decimal type that leaves out its scale, or
+// its precision as well, which MySQL reports as 0 and 10.
+var decimalPrecisionRegexp = regexp.MustCompile(`^decimal(\((\d+)\))?($|\s)`)
+
+// mariaDBQuotedDefaultVersion is the MariaDB release that started returning
+// COLUMN_DEFAULT as an SQL expression, quoting literals and spelling no
+// default as NULL.
+const mariaDBQuotedDefaultVersion = "10.2.7"
+
+func ResourceTable() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			// Lets the resource manage objects in the schemas the server
+			// maintains itself, e.g. mysql or sys.
+			"allow_system_schema": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			// Columns can't be altered in place, any change recreates the
+			// table.
+			"column": {
+				Type:     schema.TypeList,
+				Required: true,
+				ForceNew: true,
+				MinItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+							ForceNew: true,
+						},
+						"type": {
+							Type:             schema.TypeString,
+							Required:         true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressColumnTypeDiff,
+						},
+						"nullable": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							ForceNew: true,
+							Default:  true,
+						},
+						// The default is given as a literal value and quoted
+						// as a string.
+						"default": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+						},
+					},
+				},
+			},
+			"engine": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressCaseDiff,
+			},
+			"charset": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressCharsetDiff,
+			},
+			"collation": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressCharsetDiff,
+			},
+		},
+		SchemaVersion:  0,
+		MigrateState:   nil,
+		StateUpgraders: nil,
+		Create:         CreateTable,
+		Read:           ReadTable,
+		Update:         UpdateTable,
+		Delete:         DeleteTable,
+		Exists:         nil,
+		CustomizeDiff: customdiff.All(
+			validateManagedObjectSchema,
+			validateSystemObjectSchema,
+		),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		DeprecationMessage: "",
+		Timeouts:           nil,
+		Description:        "",
+	}
+}
+
+func CreateTable(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	database := d.Get("database").(string)
+	name := d.Get("name").(string)
+	if err := meta.(*MySQLConfiguration).checkManagedSchema(database); err != nil {
+		return err
+	}
+	if err := checkSystemSchema(d, database, "create a table in"); err != nil {
+		return err
+	}
+
+	stmtSQL := createTableSQL(d)
+	logStatement(stmtSQL)
+	err := execDDL(ctx, db, stmtSQL)
+	if err != nil {
+		return fmt.Errorf("Error creating table %s.%s: %s", database, name, err)
+	}
+	d.SetId(fmt.Sprintf("%s.%s", database, name))
+
+	return ReadTable(d, meta)
+}
+
+func ReadTable(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	database, name := splitTableID(d.Id())
+
+	stmtSQL := "SELECT `t`.`ENGINE`, `t`.`TABLE_COLLATION`, `c`.`CHARACTER_SET_NAME` FROM `information_schema`.`TABLES` `t`" +
+		" JOIN `information_schema`.`COLLATIONS` `c` ON `c`.`COLLATION_NAME` = `t`.`TABLE_COLLATION`" +
+		" WHERE `t`.`TABLE_SCHEMA` = ? AND `t`.`TABLE_NAME` = ? AND `t`.`TABLE_TYPE` = 'BASE TABLE'"
+	logQuery(stmtSQL)
+
+	var engine, collation, charset string
+	err := db.QueryRowContext(ctx, stmtSQL, database, name).Scan(&engine, &collation, &charset)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("Error reading table %s.%s: %s", database, name, err)
+	}
+
+	stmtSQL = "SELECT `COLUMN_NAME`, `COLUMN_TYPE`, `IS_NULLABLE`, `COLUMN_DEFAULT` FROM `information_schema`.`COLUMNS`" +
+		" WHERE `TABLE_SCHEMA` = ? AND `TABLE_NAME` = ? ORDER BY `ORDINAL_POSITION`"
+	logQuery(stmtSQL)
+
+	rows, err := db.QueryContext(ctx, stmtSQL, database, name)
+	if err != nil {
+		return fmt.Errorf("Error reading columns of table %s.%s: %s", database, name, err)
+	}
+	defer rows.Close()
+
+	quotedDefaults, err := isMariaDBVersion(meta.(*MySQLConfiguration), mariaDBQuotedDefaultVersion)
+	if err != nil {
+		return err
+	}
+
+	var columns []interface{}
+	for rows.Next() {
+		var columnName, columnType, nullable string
+		var columnDefault sql.NullString
+		if err := rows.Scan(&columnName, &columnType, &nullable, &columnDefault); err != nil {
+			return fmt.Errorf("Error reading columns of table %s.%s: %s", database, name, err)
+		}
+		columns = append(columns, map[string]interface{}{
+			"name":     columnName,
+			"type":     normalizeColumnType(columnType),
+			"nullable": nullable == "YES",
+			"default":  normalizeColumnDefault(columnDefault, quotedDefaults),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("Error reading columns of table %s.%s: %s", database, name, err)
+	}
+
+	d.Set("database", database)
+	d.Set("name", name)
+	d.Set("column", columns)
+	d.Set("engine", engine)
+	d.Set("charset", charset)
+	d.Set("collation", collation)
+
+	return nil
+}
+
+// UpdateTable only stores allow_system_schema, every other attribute forces a
+// new table.
+func UpdateTable(d *schema.ResourceData, meta interface{}) error {
+	return ReadTable(d, meta)
+}
+
+func DeleteTable(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	database, name := splitTableID(d.Id())
+	if err := meta.(*MySQLConfiguration).checkManagedSchema(database); err != nil {
+		return err
+	}
+	if err := checkSystemSchema(d, database, "drop a table in"); err != nil {
+		return err
+	}
+
+	stmtSQL := fmt.Sprintf("DROP TABLE IF EXISTS %s.%s", quoteIdentifier(database), quoteIdentifier(name))
+	logStatement(stmtSQL)
+
+	_, err := execContext(ctx, db, stmtSQL)
+	if err != nil {
+		return fmt.Errorf("Error dropping table %s.%s: %s", database, name, err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func createTableSQL(d *schema.ResourceData) string {
+	var columns []string
+	for _, c := range d.Get("column").([]interface{}) {
+		column := c.(map[string]interface{})
+
+		definition := quoteIdentifier(column["name"].(string)) + " " + column["type"].(string)
+		if !column["nullable"].(bool) {
+			definition += " NOT NULL"
+		}
+		if columnDefault := column["default"].(string); columnDefault != "" {
+			definition += " DEFAULT " + quoteString(columnDefault)
+		}
+		columns = append(columns, definition)
+	}
+
+	stmtSQL := fmt.Sprintf("CREATE TABLE %s.%s (%s)",
+		quoteIdentifier(d.Get("database").(string)),
+		quoteIdentifier(d.Get("name").(string)),
+		strings.Join(columns, ", "))
+
+	if engine := d.Get("engine").(string); engine != "" {
+		stmtSQL += " ENGINE = " + quoteIdentifier(engine)
+	}
+	if charset := d.Get("charset").(string); charset != "" {
+		stmtSQL += " DEFAULT " + defCharSetKey + quoteIdentifier(charset)
+	}
+	if collation := d.Get("collation").(string); collation != "" {
+		stmtSQL += " " + defaultCollateKey + quoteIdentifier(collation)
+	}
+
+	return stmtSQL
+}
+
+// normalizeColumnType lower-cases a column type, spells it the way MySQL
+// reports it and drops the integer display width, which only affects how
+// clients format values.
+func normalizeColumnType(columnType string) string {
+	columnType = strings.ToLower(strings.TrimSpace(columnType))
+	name := columnTypeNameRegexp.FindString(columnType)
+	if alias, ok := columnTypeAliases[name]; ok {
+		columnType = alias + columnType[len(name):]
+	}
+	if match := decimalPrecisionRegexp.FindStringSubmatch(columnType); match != nil {
+		precision := match[2]
+		if precision == "" {
+			precision = "10"
+		}
+		columnType = "decimal(" + precision + ",0)" + columnType[len(match[0])-len(match[3]):]
+	}
+	return integerDisplayWidthRegexp.ReplaceAllString(columnType, "$1")
+}
+
+func suppressColumnTypeDiff(k, old, new string, d *schema.ResourceData) bool {
+	return normalizeColumnType(old) == normalizeColumnType(new)
+}
+
+// normalizeColumnDefault turns a COLUMN_DEFAULT back into the literal value
+// the default attribute holds. With quoted set, as on newer MariaDB, values
+// come quoted and a bare NULL means there is no default.
+func normalizeColumnDefault(columnDefault sql.NullString, quoted bool) string {
+	if !columnDefault.Valid {
+		return ""
+	}
+	value := columnDefault.String
+	if !quoted {
+		return value
+	}
+	if value == "NULL" {
+		return ""
+	}
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		return strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	}
+	return value
+}
+
+// splitTableID splits a "<database>.<table>" ID at the first dot.
+func splitTableID(id string) (string, string) {
+	parts := strings.SplitN(id, ".", 2)
+	if len(parts) != 2 {
+		return id, ""
+	}
+	return parts[0], parts[1]
+}
@@ -0,0 +1,160 @@
+package mysql_provider
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestAccTable_basic(t *testing.T) {
+	database := testAccDatabasePrefix + acctest.RandString(8)
+	tableQuery := "SELECT 1 FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_NAME = 'users'"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRows(0, tableQuery, database),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name = %q
+}
+
+resource "mysql_table" "test" {
+  database = mysql_database.test.name
+  name     = "users"
+
+  column {
+    name = "id"
+    type = "integer"
+  }
+
+  column {
+    name     = "active"
+    type     = "boolean"
+    nullable = true
+  }
+
+  column {
+    name     = "balance"
+    type     = "numeric"
+    nullable = true
+  }
+}
+`, database),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRows(1, tableQuery, database),
+					resource.TestCheckResourceAttr("mysql_table.test", "column.#", "3"),
+				),
+			},
+		},
+	})
+}
+
+func TestReadTableIgnoresViews(t *testing.T) {
+	server := newFakeServer(t, func(query string) fakeResult {
+		switch {
+		case strings.Contains(query, "`information_schema`.`TABLES`"):
+			if !strings.Contains(query, "'BASE TABLE'") {
+				// A view of the same name, which has no engine.
+				return fakeResult{
+					columns: []fakeColumn{{"ENGINE", fakeTypeVarString}, {"TABLE_COLLATION", fakeTypeVarString}, {"CHARACTER_SET_NAME", fakeTypeVarString}},
+					rows:    [][]interface{}{{nil, nil, nil}},
+				}
+			}
+			return fakeResult{columns: []fakeColumn{{"ENGINE", fakeTypeVarString}}}
+		}
+		return fakeDefaultResult(query)
+	})
+	conf, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint":           server.addr(),
+		"interpolate_params": true,
+	})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+
+	d := schema.TestResourceDataRaw(t, ResourceTable().Schema, map[string]interface{}{
+		"database": "app",
+		"name":     "users",
+		"column":   []interface{}{map[string]interface{}{"name": "id", "type": "int"}},
+	})
+	d.SetId("app.users")
+	if err := ReadTable(d, conf); err != nil {
+		t.Fatalf("ReadTable returned %s", err)
+	}
+	if d.Id() != "" {
+		t.Errorf("ReadTable of a view kept the ID %q, want the table removed from state", d.Id())
+	}
+}
+
+func TestNormalizeColumnType(t *testing.T) {
+	cases := map[string]string{
+		"int(11)":          "int",
+		"INT(10) UNSIGNED": "int unsigned",
+		"bigint(20)":       "bigint",
+		"varchar(255)":     "varchar(255)",
+		"decimal(10,2)":    "decimal(10,2)",
+		" tinyint(1) ":     "tinyint",
+		"point":            "point",
+		"integer":          "int",
+		"INTEGER UNSIGNED": "int unsigned",
+		"bool":             "tinyint",
+		"BOOLEAN":          "tinyint",
+		"numeric(10,2)":    "decimal(10,2)",
+		"dec(8,3)":         "decimal(8,3)",
+		"fixed(6,1)":       "decimal(6,1)",
+		"decimal":          "decimal(10,0)",
+		"numeric(5)":       "decimal(5,0)",
+		"decimal unsigned": "decimal(10,0) unsigned",
+		"decimal(12,4)":    "decimal(12,4)",
+		"double":           "double",
+		"datetime":         "datetime",
+	}
+	for in, expected := range cases {
+		if got := normalizeColumnType(in); got != expected {
+			t.Errorf("normalizeColumnType(%q) = %q, want %q", in, got, expected)
+		}
+	}
+}
+
+func TestNormalizeColumnDefault(t *testing.T) {
+	cases := []struct {
+		columnDefault sql.NullString
+		quoted        bool
+		expected      string
+	}{
+		{sql.NullString{}, false, ""},
+		{sql.NullString{}, true, ""},
+		{sql.NullString{String: "abc", Valid: true}, false, "abc"},
+		{sql.NullString{String: "'abc'", Valid: true}, true, "abc"},
+		{sql.NullString{String: "'it''s'", Valid: true}, true, "it's"},
+		{sql.NullString{String: "NULL", Valid: true}, true, ""},
+		{sql.NullString{String: "NULL", Valid: true}, false, "NULL"},
+		{sql.NullString{String: "current_timestamp()", Valid: true}, true, "current_timestamp()"},
+	}
+	for _, c := range cases {
+		if got := normalizeColumnDefault(c.columnDefault, c.quoted); got != c.expected {
+			t.Errorf("normalizeColumnDefault(%v, %t) = %q, want %q", c.columnDefault, c.quoted, got, c.expected)
+		}
+	}
+}
+
+func TestSplitTableID(t *testing.T) {
+	cases := map[string][2]string{
+		"app.users":   {"app", "users"},
+		"app.my.tbl":  {"app", "my.tbl"},
+		"no_database": {"no_database", ""},
+	}
+	for id, expected := range cases {
+		database, name := splitTableID(id)
+		if database != expected[0] || name != expected[1] {
+			t.Errorf("splitTableID(%q) = %q, %q, want %q, %q", id, database, name, expected[0], expected[1])
+		}
+	}
+}
@@ -0,0 +1,321 @@
+package mysql_provider
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// userAuthPlugins maps the provider's authentication_plugin names onto the
+// server side plugins they correspond to.
+var userAuthPlugins = map[string]string{
+	nativePasswords:      "mysql_native_password",
+	cachingSHA2Passwords: cachingSHA2Plugin,
+}
+
+// clientAuthPlugins only exist on the client side. mysql_clear_password sends
+// the password to a server plugin such as PAM or LDAP, it can't identify an
+// account itself.
+var clientAuthPlugins = map[string]bool{
+	cleartextPasswords:     true,
+	"mysql_clear_password": true,
+}
+
+// passwordlessAuthPlugins are the server side plugins that authenticate
+// without a password.
+var passwordlessAuthPlugins = map[string]bool{
+	"auth_socket": true,
+	"unix_socket": true,
+}
+
+// userResourceLimits maps the resource limit attributes onto their ALTER USER
+// options and the mysql.user columns they are stored in, 0 means unlimited.
+var userResourceLimits = []struct {
+	key    string
+	option string
+	column string
+}{
+	{"max_queries_per_hour", "MAX_QUERIES_PER_HOUR", "max_questions"},
+	{"max_updates_per_hour", "MAX_UPDATES_PER_HOUR", "max_updates"},
+	{"max_connections_per_hour", "MAX_CONNECTIONS_PER_HOUR", "max_connections"},
+	{"max_user_connections", "MAX_USER_CONNECTIONS", "max_user_connections"},
+}
+
+func ResourceUser() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"user": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			// Defaults to the provider's default_user_host.
+			"host": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Computed: true,
+			},
+			"plaintext_password": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
+			},
+			// Changing any value re-applies plaintext_password, e.g. to rotate
+			// it from a secret store without the password showing in the diff.
+			"password_triggers": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"auth_plugin": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				DiffSuppressFunc: suppressAuthPluginDiff,
+				ValidateFunc:     validateUserAuthPlugin,
+			},
+			"tls_option": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "NONE",
+				ValidateFunc: validation.StringInSlice([]string{
+					"NONE",
+					"SSL",
+					"X509",
+				}, false),
+			},
+			"max_queries_per_hour": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"max_updates_per_hour": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"max_connections_per_hour": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"max_user_connections": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+		},
+		SchemaVersion:      0,
+		MigrateState:       nil,
+		StateUpgraders:     nil,
+		Create:             CreateUser,
+		Read:               ReadUser,
+		Update:             UpdateUser,
+		Delete:             DeleteUser,
+		Exists:             nil,
+		CustomizeDiff:      nil,
+		Importer:           nil,
+		DeprecationMessage: "",
+		Timeouts:           nil,
+		Description:        "",
+	}
+}
+
+func CreateUser(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	applyDefaultUserHost(d, "host", meta)
+
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+
+	stmtSQL := fmt.Sprintf("CREATE USER %s%s REQUIRE %s%s",
+		accountName(user, host),
+		identifiedClause(d),
+		d.Get("tls_option").(string),
+		resourceLimitsClause(d, false),
+	)
+	logStatement(stmtSQL)
+
+	_, err := execContext(ctx, db, stmtSQL)
+	if err != nil {
+		return fmt.Errorf("Error creating user %s: %s", accountName(user, host), err)
+	}
+	d.SetId(fmt.Sprintf("%s@%s", user, host))
+
+	return ReadUser(d, meta)
+}
+
+func ReadUser(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+
+	stmtSQL := "SELECT `Host`, `plugin`, `max_questions`, `max_updates`, `max_connections`, `max_user_connections` FROM `mysql`.`user` WHERE `User` = ? AND `Host` = ?"
+	logQuery(stmtSQL)
+
+	var readHost, plugin string
+	limits := make([]int, len(userResourceLimits))
+	err := db.QueryRowContext(ctx, stmtSQL, user, host).Scan(&readHost, &plugin, &limits[0], &limits[1], &limits[2], &limits[3])
+	if err != nil {
+		if err == sql.ErrNoRows {
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("Error reading user %s: %s", accountName(user, host), err)
+	}
+
+	d.Set("host", readHost)
+	d.Set("auth_plugin", plugin)
+	for i, limit := range userResourceLimits {
+		d.Set(limit.key, limits[i])
+	}
+
+	return nil
+}
+
+func UpdateUser(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+
+	var stmts []string
+	if d.HasChange("plaintext_password") || d.HasChange("auth_plugin") || d.HasChange("password_triggers") {
+		clause, err := alterIdentifiedClause(d)
+		if err != nil {
+			return fmt.Errorf("Error updating user %s: %s", accountName(user, host), err)
+		}
+		stmts = append(stmts, fmt.Sprintf("ALTER USER %s%s", accountName(user, host), clause))
+	}
+	if d.HasChange("tls_option") {
+		stmts = append(stmts, fmt.Sprintf("ALTER USER %s REQUIRE %s", accountName(user, host), d.Get("tls_option").(string)))
+	}
+	if clause := resourceLimitsClause(d, true); clause != "" {
+		stmts = append(stmts, fmt.Sprintf("ALTER USER %s%s", accountName(user, host), clause))
+	}
+
+	for _, stmtSQL := range stmts {
+		logStatement(stmtSQL)
+		_, err := execContext(ctx, db, stmtSQL)
+		if err != nil {
+			return fmt.Errorf("Error updating user %s: %s", accountName(user, host), err)
+		}
+	}
+
+	return ReadUser(d, meta)
+}
+
+func DeleteUser(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+
+	stmtSQL := "DROP USER " + accountName(user, host)
+	logStatement(stmtSQL)
+
+	_, err := execContext(ctx, db, stmtSQL)
+	if err != nil {
+		return fmt.Errorf("Error dropping user %s: %s", accountName(user, host), err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// applyDefaultUserHost fills in the provider's default_user_host when the
+// resource omits the host attribute key.
+func applyDefaultUserHost(d *schema.ResourceData, key string, meta interface{}) {
+	if _, ok := d.GetOk(key); !ok {
+		d.Set(key, meta.(*MySQLConfiguration).DefaultUserHost)
+	}
+}
+
+func accountName(user string, host string) string {
+	return quoteString(user) + "@" + quoteString(host)
+}
+
+func identifiedClause(d *schema.ResourceData) string {
+	password := d.Get("plaintext_password").(string)
+	plugin := userAuthPlugin(d.Get("auth_plugin").(string))
+
+	switch {
+	case plugin != "" && password != "":
+		return fmt.Sprintf(" IDENTIFIED WITH %s BY %s", quoteIdentifier(plugin), quoteString(password))
+	case plugin != "":
+		return fmt.Sprintf(" IDENTIFIED WITH %s", quoteIdentifier(plugin))
+	case password != "":
+		return fmt.Sprintf(" IDENTIFIED BY %s", quoteString(password))
+	}
+
+	return ""
+}
+
+// alterIdentifiedClause is identifiedClause for an existing account. ALTER
+// USER ... IDENTIFIED WITH without BY empties the password, so that is only
+// sent when auth_plugin changed to a plugin that takes no password.
+func alterIdentifiedClause(d *schema.ResourceData) (string, error) {
+	plugin := userAuthPlugin(d.Get("auth_plugin").(string))
+	if d.Get("plaintext_password").(string) != "" {
+		return identifiedClause(d), nil
+	}
+	if d.HasChange("auth_plugin") && passwordlessAuthPlugins[plugin] {
+		return fmt.Sprintf(" IDENTIFIED WITH %s", quoteIdentifier(plugin)), nil
+	}
+	return "", fmt.Errorf("plaintext_password is empty, refusing to reset the password to an empty one")
+}
+
+// resourceLimitsClause builds the WITH clause of the resource limits that are
+// set, or that changed when changed is set, so clearing a limit sends its 0.
+func resourceLimitsClause(d *schema.ResourceData, changed bool) string {
+	var options []string
+	for _, limit := range userResourceLimits {
+		if changed && !d.HasChange(limit.key) || !changed && d.Get(limit.key).(int) == 0 {
+			continue
+		}
+		options = append(options, fmt.Sprintf("%s %d", limit.option, d.Get(limit.key).(int)))
+	}
+	if len(options) == 0 {
+		return ""
+	}
+	return " WITH " + strings.Join(options, " ")
+}
+
+func userAuthPlugin(plugin string) string {
+	if serverPlugin, ok := userAuthPlugins[strings.ToLower(plugin)]; ok {
+		return serverPlugin
+	}
+	return plugin
+}
+
+func validateUserAuthPlugin(v interface{}, k string) (ws []string, errs []error) {
+	if clientAuthPlugins[strings.ToLower(v.(string))] {
+		errs = append(errs, fmt.Errorf("%s: %s is a client side plugin, accounts need a server side one such as mysql_native_password", k, v))
+	}
+	return
+}
+
+func suppressAuthPluginDiff(k, old, new string, d *schema.ResourceData) bool {
+	if new == "" {
+		return true
+	}
+	return userAuthPlugin(old) == userAuthPlugin(new)
+}
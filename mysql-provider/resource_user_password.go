@@ -0,0 +1,177 @@
+package mysql_provider
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"fmt"
+	"math/big"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+const (
+	passwordAlphanumeric = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	passwordSpecial      = "!#$%&*()-_=+[]{}<>:?"
+)
+
+func ResourceUserPassword() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"user": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			// Defaults to the provider's default_user_host.
+			"host": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Computed: true,
+			},
+			"length": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      20,
+				ValidateFunc: validation.IntBetween(8, 32),
+			},
+			"special": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+				Default:  true,
+			},
+			// Replaces the password with a throwaway one on destroy, so the
+			// generated password stops working once it leaves the state.
+			"reset_on_destroy": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"password": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+		},
+		SchemaVersion:      0,
+		MigrateState:       nil,
+		StateUpgraders:     nil,
+		Create:             CreateUserPassword,
+		Read:               ReadUserPassword,
+		Update:             UpdateUserPassword,
+		Delete:             DeleteUserPassword,
+		Exists:             nil,
+		CustomizeDiff:      nil,
+		Importer:           nil,
+		DeprecationMessage: "",
+		Timeouts:           nil,
+		Description:        "",
+	}
+}
+
+func CreateUserPassword(d *schema.ResourceData, meta interface{}) error {
+	applyDefaultUserHost(d, "host", meta)
+
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+
+	password, err := generatePassword(d.Get("length").(int), d.Get("special").(bool))
+	if err != nil {
+		return err
+	}
+
+	err = setUserPassword(meta, user, host, password)
+	if err != nil {
+		return err
+	}
+	d.SetId(fmt.Sprintf("%s@%s", user, host))
+	d.Set("password", password)
+
+	return ReadUserPassword(d, meta)
+}
+
+func ReadUserPassword(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+
+	stmtSQL := "SELECT `User` FROM `mysql`.`user` WHERE `User` = ? AND `Host` = ?"
+	logQuery(stmtSQL)
+
+	var _user string
+	err := db.QueryRowContext(ctx, stmtSQL, user, host).Scan(&_user)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("Error reading user %s: %s", accountName(user, host), err)
+	}
+
+	return nil
+}
+
+// UpdateUserPassword only ever sees reset_on_destroy change, everything else
+// forces a new password.
+func UpdateUserPassword(d *schema.ResourceData, meta interface{}) error {
+	return ReadUserPassword(d, meta)
+}
+
+func DeleteUserPassword(d *schema.ResourceData, meta interface{}) error {
+	if d.Get("reset_on_destroy").(bool) {
+		password, err := generatePassword(d.Get("length").(int), d.Get("special").(bool))
+		if err != nil {
+			return err
+		}
+		err = setUserPassword(meta, d.Get("user").(string), d.Get("host").(string), password)
+		if err != nil {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func setUserPassword(meta interface{}, user string, host string, password string) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	stmtSQL := fmt.Sprintf("ALTER USER %s IDENTIFIED BY %s", accountName(user, host), quoteString(password))
+	logStatement(stmtSQL)
+
+	_, err := execContext(ctx, db, stmtSQL)
+	if err != nil {
+		return fmt.Errorf("Error setting password of %s: %s", accountName(user, host), err)
+	}
+
+	return nil
+}
+
+// generatePassword draws length characters uniformly from the alphanumerics,
+// plus a set of special characters when special is set, using crypto/rand.
+func generatePassword(length int, special bool) (string, error) {
+	alphabet := passwordAlphanumeric
+	if special {
+		alphabet += passwordSpecial
+	}
+
+	max := big.NewInt(int64(len(alphabet)))
+	password := make([]byte, length)
+	for i := range password {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("Could not generate password: %s", err)
+		}
+		password[i] = alphabet[n.Int64()]
+	}
+
+	return string(password), nil
+}
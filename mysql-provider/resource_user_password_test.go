@@ -0,0 +1,85 @@
+package mysql_provider
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccUserPassword_basic(t *testing.T) {
+	user := testAccDatabasePrefix + acctest.RandString(8)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRows(0, "SELECT 1 FROM mysql.user WHERE User = ?", user),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "mysql_user" "test" {
+  user               = %q
+  host               = "localhost"
+  plaintext_password = "Initial0!"
+}
+
+resource "mysql_user_password" "test" {
+  user    = mysql_user.test.user
+  host    = mysql_user.test.host
+  length  = 24
+  special = false
+}
+`, user),
+				Check: func(s *terraform.State) error {
+					password := s.RootModule().Resources["mysql_user_password.test"].Primary.Attributes["password"]
+					if len(password) != 24 || strings.Trim(password, passwordAlphanumeric) != "" {
+						return fmt.Errorf("Generated password %q is not 24 alphanumerics", password)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestGeneratePassword(t *testing.T) {
+	for _, length := range []int{8, 20, 32} {
+		for _, special := range []bool{false, true} {
+			alphabet := passwordAlphanumeric
+			if special {
+				alphabet += passwordSpecial
+			}
+
+			// Special characters are drawn with a small chance only, so
+			// look for them across many passwords.
+			sawSpecial := false
+			for i := 0; i < 50; i++ {
+				password, err := generatePassword(length, special)
+				if err != nil {
+					t.Fatalf("generatePassword(%d, %t) returned %s", length, special, err)
+				}
+				if len(password) != length {
+					t.Errorf("generatePassword(%d, %t) = %q, want %d characters", length, special, password, length)
+				}
+				if strings.Trim(password, alphabet) != "" {
+					t.Errorf("generatePassword(%d, %t) = %q, has characters outside %q", length, special, password, alphabet)
+				}
+				if strings.ContainsAny(password, passwordSpecial) {
+					sawSpecial = true
+				}
+			}
+			if sawSpecial != special {
+				t.Errorf("generatePassword(%d, %t) used special characters: %t", length, special, sawSpecial)
+			}
+		}
+	}
+
+	first, _ := generatePassword(20, true)
+	second, _ := generatePassword(20, true)
+	if first == second {
+		t.Errorf("generatePassword returned %q twice", first)
+	}
+}
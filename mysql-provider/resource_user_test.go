@@ -0,0 +1,294 @@
+package mysql_provider
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccUser_basic(t *testing.T) {
+	user := testAccDatabasePrefix + acctest.RandString(8)
+	userQuery := "SELECT 1 FROM mysql.user WHERE User = ? AND Host = 'localhost'"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRows(0, userQuery, user),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfig(user, "Initial-Passw0rd"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRows(1, userQuery, user),
+					resource.TestCheckResourceAttr("mysql_user.test", "id", user+"@localhost"),
+					resource.TestCheckResourceAttr("mysql_user.test", "host", "localhost"),
+				),
+			},
+			{
+				Config: testAccUserConfig(user, "Rotated-Passw0rd"),
+				Check:  testAccCheckRows(1, userQuery, user),
+			},
+		},
+	})
+}
+
+func TestAccUser_defaultHost(t *testing.T) {
+	user := testAccDatabasePrefix + acctest.RandString(8)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRows(0, "SELECT 1 FROM mysql.user WHERE User = ?", user),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "mysql" {
+  default_user_host = "10.0.0.%%"
+}
+
+resource "mysql_user" "test" {
+  user               = %q
+  plaintext_password = "Passw0rd!"
+}
+`, user),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_user.test", "host", "10.0.0.%"),
+					testAccCheckRows(1, "SELECT 1 FROM mysql.user WHERE User = ? AND Host = '10.0.0.%'", user),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUser_resourceLimits(t *testing.T) {
+	user := testAccDatabasePrefix + acctest.RandString(8)
+	limitsQuery := "SELECT 1 FROM mysql.user WHERE User = ? AND Host = 'localhost' AND max_questions = ? AND max_user_connections = ?"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRows(0, "SELECT 1 FROM mysql.user WHERE User = ?", user),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserResourceLimitsConfig(user, "max_queries_per_hour = 100\n  max_user_connections = 5"),
+				Check:  testAccCheckRows(1, limitsQuery, user, 100, 5),
+			},
+			{
+				Config: testAccUserResourceLimitsConfig(user, ""),
+				Check:  testAccCheckRows(1, limitsQuery, user, 0, 0),
+			},
+		},
+	})
+}
+
+func testAccUserConfig(user string, password string) string {
+	return fmt.Sprintf(`
+resource "mysql_user" "test" {
+  user               = %q
+  host               = "localhost"
+  plaintext_password = %q
+}
+`, user, password)
+}
+
+func testAccUserResourceLimitsConfig(user string, limits string) string {
+	return fmt.Sprintf(`
+resource "mysql_user" "test" {
+  user               = %q
+  host               = "localhost"
+  plaintext_password = "Passw0rd!"
+  %s
+}
+`, user, limits)
+}
+
+func TestIdentifiedClause(t *testing.T) {
+	cases := []struct {
+		raw      map[string]interface{}
+		expected string
+	}{
+		{map[string]interface{}{"user": "app"}, ""},
+		{map[string]interface{}{"user": "app", "plaintext_password": "it's"}, " IDENTIFIED BY 'it''s'"},
+		{map[string]interface{}{"user": "app", "auth_plugin": "auth_socket"}, " IDENTIFIED WITH `auth_socket`"},
+		{
+			map[string]interface{}{"user": "app", "auth_plugin": nativePasswords, "plaintext_password": "secret"},
+			" IDENTIFIED WITH `mysql_native_password` BY 'secret'",
+		},
+	}
+	for _, c := range cases {
+		d := schema.TestResourceDataRaw(t, ResourceUser().Schema, c.raw)
+		if got := identifiedClause(d); got != c.expected {
+			t.Errorf("identifiedClause(%v) = %q, want %q", c.raw, got, c.expected)
+		}
+	}
+}
+
+func TestValidateUserAuthPlugin(t *testing.T) {
+	for _, plugin := range []string{"native", "mysql_native_password", "auth_socket"} {
+		if _, errs := validateUserAuthPlugin(plugin, "auth_plugin"); len(errs) != 0 {
+			t.Errorf("validateUserAuthPlugin(%q) returned %v", plugin, errs)
+		}
+	}
+	for _, plugin := range []string{"cleartext", "mysql_clear_password", "MYSQL_CLEAR_PASSWORD"} {
+		if _, errs := validateUserAuthPlugin(plugin, "auth_plugin"); len(errs) == 0 {
+			t.Errorf("validateUserAuthPlugin(%q) returned no errors", plugin)
+		}
+	}
+}
+
+func TestAccountNameInjection(t *testing.T) {
+	cases := []struct {
+		user     string
+		host     string
+		expected string
+	}{
+		{"app", "%", `'app'@'%'`},
+		{"app", `%'; DROP USER 'root'@'%`, `'app'@'%''; DROP USER ''root''@''%'`},
+		{`app\`, `\'`, `'app\\'@'\\'''`},
+		{"app' OR '1'='1", "localhost", `'app'' OR ''1''=''1'@'localhost'`},
+	}
+	for _, c := range cases {
+		if got := accountName(c.user, c.host); got != c.expected {
+			t.Errorf("accountName(%q, %q) = %s, want %s", c.user, c.host, got, c.expected)
+		}
+	}
+}
+
+func TestUpdateUserPasswordTriggers(t *testing.T) {
+	server := newFakeServer(t, func(query string) fakeResult {
+		if strings.HasPrefix(query, "SELECT `Host`, `plugin`") {
+			return fakeResult{
+				columns: []fakeColumn{
+					{"Host", fakeTypeVarString},
+					{"plugin", fakeTypeVarString},
+					{"max_questions", fakeTypeLongLong},
+					{"max_updates", fakeTypeLongLong},
+					{"max_connections", fakeTypeLongLong},
+					{"max_user_connections", fakeTypeLongLong},
+				},
+				rows: [][]interface{}{{"localhost", "caching_sha2_password", "0", "0", "0", "0"}},
+			}
+		}
+		return fakeDefaultResult(query)
+	})
+	conf, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint":           server.addr(),
+		"interpolate_params": true,
+	})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+
+	state := map[string]string{
+		"user":                       "app",
+		"host":                       "localhost",
+		"plaintext_password":         "secret",
+		"password_triggers.%":        "1",
+		"password_triggers.rotation": "1",
+	}
+	d := testResourceDataDiff(t, ResourceUser(), state, map[string]interface{}{
+		"user":               "app",
+		"host":               "localhost",
+		"plaintext_password": "secret",
+		"password_triggers":  map[string]interface{}{"rotation": "2"},
+	})
+	if err := UpdateUser(d, conf); err != nil {
+		t.Fatalf("UpdateUser returned %s", err)
+	}
+	if !fakeQueriesContain(server.receivedQueries(), "ALTER USER 'app'@'localhost' IDENTIFIED BY 'secret'") {
+		t.Errorf("UpdateUser didn't reset the password, got %q", server.receivedQueries())
+	}
+}
+
+func TestAlterIdentifiedClause(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, ResourceUser().Schema, map[string]interface{}{
+		"user":               "app",
+		"plaintext_password": "secret",
+	})
+	if got, err := alterIdentifiedClause(d); err != nil || got != " IDENTIFIED BY 'secret'" {
+		t.Errorf("alterIdentifiedClause with a password = %q, %v", got, err)
+	}
+
+	d = schema.TestResourceDataRaw(t, ResourceUser().Schema, map[string]interface{}{
+		"user":        "app",
+		"auth_plugin": "auth_socket",
+	})
+	if got, err := alterIdentifiedClause(d); err != nil || got != " IDENTIFIED WITH `auth_socket`" {
+		t.Errorf("alterIdentifiedClause switching to auth_socket = %q, %v", got, err)
+	}
+
+	// Without a password and an auth_plugin change, ALTER USER would empty
+	// the password.
+	d = ResourceUser().Data(&terraform.InstanceState{
+		ID: "app@%",
+		Attributes: map[string]string{
+			"user":        "app",
+			"auth_plugin": "auth_socket",
+		},
+	})
+	if got, err := alterIdentifiedClause(d); err == nil {
+		t.Errorf("alterIdentifiedClause without a password or plugin change = %q, want an error", got)
+	}
+
+	d = schema.TestResourceDataRaw(t, ResourceUser().Schema, map[string]interface{}{
+		"user":        "app",
+		"auth_plugin": "mysql_native_password",
+	})
+	if got, err := alterIdentifiedClause(d); err == nil {
+		t.Errorf("alterIdentifiedClause switching to a password plugin without a password = %q, want an error", got)
+	}
+}
+
+func TestResourceLimitsClause(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, ResourceUser().Schema, map[string]interface{}{"user": "app"})
+	if got := resourceLimitsClause(d, false); got != "" {
+		t.Errorf("resourceLimitsClause without limits = %q, want none", got)
+	}
+
+	d = schema.TestResourceDataRaw(t, ResourceUser().Schema, map[string]interface{}{
+		"user":                 "app",
+		"max_queries_per_hour": 100,
+		"max_user_connections": 5,
+	})
+	expected := " WITH MAX_QUERIES_PER_HOUR 100 MAX_USER_CONNECTIONS 5"
+	if got := resourceLimitsClause(d, false); got != expected {
+		t.Errorf("resourceLimitsClause = %q, want %q", got, expected)
+	}
+
+	// Clearing a limit sends its 0, unchanged limits are left out.
+	state := map[string]string{
+		"user":                 "app",
+		"host":                 "localhost",
+		"max_queries_per_hour": "100",
+		"max_user_connections": "5",
+	}
+	d = testResourceDataDiff(t, ResourceUser(), state, map[string]interface{}{
+		"user":                 "app",
+		"host":                 "localhost",
+		"max_user_connections": 5,
+	})
+	expected = " WITH MAX_QUERIES_PER_HOUR 0"
+	if got := resourceLimitsClause(d, true); got != expected {
+		t.Errorf("resourceLimitsClause clearing a limit = %q, want %q", got, expected)
+	}
+}
+
+func TestApplyDefaultUserHost(t *testing.T) {
+	conf := &MySQLConfiguration{DefaultUserHost: "10.0.0.%"}
+
+	d := schema.TestResourceDataRaw(t, ResourceUser().Schema, map[string]interface{}{"user": "app"})
+	applyDefaultUserHost(d, "host", conf)
+	if got := d.Get("host").(string); got != "10.0.0.%" {
+		t.Errorf("host = %q without a host, want the provider default", got)
+	}
+
+	d = schema.TestResourceDataRaw(t, ResourceUser().Schema, map[string]interface{}{"user": "app", "host": "localhost"})
+	applyDefaultUserHost(d, "host", conf)
+	if got := d.Get("host").(string); got != "localhost" {
+		t.Errorf("host = %q, want the resource's localhost", got)
+	}
+}
@@ -0,0 +1,181 @@
+package mysql_provider
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func ResourceView() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			// Lets the resource manage objects in the schemas the server
+			// maintains itself, e.g. mysql or sys.
+			"allow_system_schema": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"statement": {
+				Type:     schema.TypeString,
+				Required: true,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return normalizeWhitespace(old) == normalizeWhitespace(new)
+				},
+			},
+			// The server rewrites the statement, so drift is detected against
+			// the definition it reported after the last apply.
+			"definition": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+		SchemaVersion:  0,
+		MigrateState:   nil,
+		StateUpgraders: nil,
+		Create:         CreateView,
+		Read:           ReadView,
+		Update:         UpdateView,
+		Delete:         DeleteView,
+		Exists:         nil,
+		CustomizeDiff: customdiff.All(
+			validateManagedObjectSchema,
+			validateSystemObjectSchema,
+		),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		DeprecationMessage: "",
+		Timeouts:           nil,
+		Description:        "",
+	}
+}
+
+func CreateView(d *schema.ResourceData, meta interface{}) error {
+	database := d.Get("database").(string)
+	name := d.Get("name").(string)
+	if err := meta.(*MySQLConfiguration).checkManagedSchema(database); err != nil {
+		return err
+	}
+	if err := checkSystemSchema(d, database, "create a view in"); err != nil {
+		return err
+	}
+
+	err := replaceView(d, meta)
+	if err != nil {
+		return err
+	}
+	d.SetId(fmt.Sprintf("%s.%s", database, name))
+
+	return ReadView(d, meta)
+}
+
+func ReadView(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	database, name := splitTableID(d.Id())
+
+	stmtSQL := "SELECT `VIEW_DEFINITION` FROM `information_schema`.`VIEWS` WHERE `TABLE_SCHEMA` = ? AND `TABLE_NAME` = ?"
+	logQuery(stmtSQL)
+
+	var definition string
+	err := db.QueryRowContext(ctx, stmtSQL, database, name).Scan(&definition)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("Error reading view %s.%s: %s", database, name, err)
+	}
+
+	// Only a definition changed out-of-band replaces the configured
+	// statement, an imported view has none to compare against yet.
+	previous := d.Get("definition").(string)
+	if (previous == "" && d.Get("statement").(string) == "") ||
+		(previous != "" && normalizeWhitespace(previous) != normalizeWhitespace(definition)) {
+		d.Set("statement", definition)
+	}
+
+	d.Set("database", database)
+	d.Set("name", name)
+	d.Set("definition", definition)
+
+	return nil
+}
+
+func UpdateView(d *schema.ResourceData, meta interface{}) error {
+	if d.HasChange("statement") {
+		err := replaceView(d, meta)
+		if err != nil {
+			return err
+		}
+		// Make ReadView take the new definition as is.
+		d.Set("definition", "")
+	}
+
+	return ReadView(d, meta)
+}
+
+func DeleteView(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	database, name := splitTableID(d.Id())
+	if err := meta.(*MySQLConfiguration).checkManagedSchema(database); err != nil {
+		return err
+	}
+	if err := checkSystemSchema(d, database, "drop a view in"); err != nil {
+		return err
+	}
+
+	stmtSQL := fmt.Sprintf("DROP VIEW IF EXISTS %s.%s", quoteIdentifier(database), quoteIdentifier(name))
+	logStatement(stmtSQL)
+
+	_, err := execContext(ctx, db, stmtSQL)
+	if err != nil {
+		return fmt.Errorf("Error dropping view %s.%s: %s", database, name, err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func replaceView(d *schema.ResourceData, meta interface{}) error {
+	db := meta.(*MySQLConfiguration).connection()
+	ctx, cancel := meta.(*MySQLConfiguration).statementContext()
+	defer cancel()
+
+	database := d.Get("database").(string)
+	name := d.Get("name").(string)
+
+	stmtSQL := fmt.Sprintf("CREATE OR REPLACE VIEW %s.%s AS %s", quoteIdentifier(database), quoteIdentifier(name), d.Get("statement").(string))
+	logStatement(stmtSQL)
+
+	err := execDDL(ctx, db, stmtSQL)
+	if err != nil {
+		return fmt.Errorf("Error creating view %s.%s: %s", database, name, err)
+	}
+
+	return nil
+}
+
+// normalizeWhitespace collapses runs of whitespace into single spaces.
+func normalizeWhitespace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
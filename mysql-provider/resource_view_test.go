@@ -0,0 +1,71 @@
+package mysql_provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccView_basic(t *testing.T) {
+	database := testAccDatabasePrefix + acctest.RandString(8)
+	viewQuery := "SELECT 1 FROM information_schema.VIEWS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = 'active_users' AND VIEW_DEFINITION LIKE ?"
+	config := func(statement string) string {
+		return fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name = %q
+}
+
+resource "mysql_table" "test" {
+  database = mysql_database.test.name
+  name     = "users"
+
+  column {
+    name = "id"
+    type = "int"
+  }
+
+  column {
+    name = "active"
+    type = "tinyint"
+  }
+}
+
+resource "mysql_view" "test" {
+  database  = mysql_table.test.database
+  name      = "active_users"
+  statement = %q
+}
+`, database, statement)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRows(0, "SELECT 1 FROM information_schema.VIEWS WHERE TABLE_SCHEMA = ?", database),
+		Steps: []resource.TestStep{
+			{
+				Config: config(fmt.Sprintf("SELECT id FROM %s.users WHERE active = 1", database)),
+				Check:  testAccCheckRows(1, viewQuery, database, "%`active` = 1%"),
+			},
+			{
+				Config: config(fmt.Sprintf("SELECT id\n  FROM %s.users\n  WHERE active = 0", database)),
+				Check:  testAccCheckRows(1, viewQuery, database, "%`active` = 0%"),
+			},
+		},
+	})
+}
+
+func TestNormalizeWhitespace(t *testing.T) {
+	cases := map[string]string{
+		"SELECT 1":                  "SELECT 1",
+		"  SELECT\n\tid  FROM t \n": "SELECT id FROM t",
+		"":                          "",
+	}
+	for in, expected := range cases {
+		if got := normalizeWhitespace(in); got != expected {
+			t.Errorf("normalizeWhitespace(%q) = %q, want %q", in, got, expected)
+		}
+	}
+}
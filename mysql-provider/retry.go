@@ -0,0 +1,39 @@
+package mysql_provider
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+const maxConnectRetryInterval = 30 * time.Second
+
+// retryWithBackoff calls f until it succeeds, returns a non-retryable error or
+// timeout runs out. The wait between attempts starts at interval and doubles
+// up to maxConnectRetryInterval, each wait is jittered down by up to half so
+// providers started together don't retry in lockstep. A timeout of zero
+// makes a single attempt and returns its error as is.
+func retryWithBackoff(timeout time.Duration, interval time.Duration, f func() *resource.RetryError) error {
+	jitter := rand.New(rand.NewSource(time.Now().UnixNano()))
+	deadline := time.Now().Add(timeout)
+	for {
+		retryErr := f()
+		if retryErr == nil {
+			return nil
+		}
+		if !retryErr.Retryable || timeout <= 0 {
+			return retryErr.Err
+		}
+
+		wait := interval/2 + time.Duration(jitter.Int63n(int64(interval/2)+1))
+		if time.Now().Add(wait).After(deadline) {
+			return &resource.TimeoutError{LastError: retryErr.Err, Timeout: timeout}
+		}
+		time.Sleep(wait)
+
+		if interval *= 2; interval > maxConnectRetryInterval {
+			interval = maxConnectRetryInterval
+		}
+	}
+}
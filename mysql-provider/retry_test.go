@@ -0,0 +1,75 @@
+package mysql_provider
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestRetryWithBackoffSingleAttempt(t *testing.T) {
+	attempts := 0
+	failure := errors.New("connection refused")
+	err := retryWithBackoff(0, time.Millisecond, func() *resource.RetryError {
+		attempts++
+		return resource.RetryableError(failure)
+	})
+	if attempts != 1 {
+		t.Errorf("retryWithBackoff without a timeout made %d attempts, want 1", attempts)
+	}
+	if err != failure {
+		t.Errorf("retryWithBackoff returned %v, want %v", err, failure)
+	}
+}
+
+func TestRetryWithBackoffRetries(t *testing.T) {
+	attempts := 0
+	err := retryWithBackoff(time.Second, time.Millisecond, func() *resource.RetryError {
+		if attempts++; attempts < 3 {
+			return resource.RetryableError(errors.New("connection refused"))
+		}
+		return nil
+	})
+	if err != nil || attempts != 3 {
+		t.Errorf("retryWithBackoff = %v after %d attempts, want success after 3", err, attempts)
+	}
+}
+
+func TestRetryWithBackoffNonRetryable(t *testing.T) {
+	attempts := 0
+	failure := errors.New("access denied")
+	err := retryWithBackoff(time.Second, time.Millisecond, func() *resource.RetryError {
+		attempts++
+		return resource.NonRetryableError(failure)
+	})
+	if attempts != 1 || err != failure {
+		t.Errorf("retryWithBackoff = %v after %d attempts, want %v after 1", err, attempts, failure)
+	}
+}
+
+func TestRetryWithBackoffTimeout(t *testing.T) {
+	err := retryWithBackoff(5*time.Millisecond, time.Millisecond, func() *resource.RetryError {
+		return resource.RetryableError(errors.New("connection refused"))
+	})
+	if _, ok := err.(*resource.TimeoutError); !ok {
+		t.Errorf("retryWithBackoff returned %v, want a timeout", err)
+	}
+}
+
+func TestRetryWithBackoffWindow(t *testing.T) {
+	// The waits start between 5ms and 10ms and double, so 3 to 5 attempts
+	// fit into 100ms.
+	attempts := 0
+	start := time.Now()
+	retryWithBackoff(100*time.Millisecond, 10*time.Millisecond, func() *resource.RetryError {
+		attempts++
+		return resource.RetryableError(errors.New("connection refused"))
+	})
+	if attempts < 3 || attempts > 5 {
+		t.Errorf("retryWithBackoff made %d attempts in 100ms, want 3 to 5", attempts)
+	}
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("retryWithBackoff returned after %s, want it to give up within its 100ms", elapsed)
+	}
+}
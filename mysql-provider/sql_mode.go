@@ -0,0 +1,103 @@
+package mysql_provider
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// knownSQLModes are the modes and combination modes understood by MySQL 5.7,
+// 8.0 and MariaDB.
+var knownSQLModes = map[string]bool{
+	"ALLOW_INVALID_DATES":        true,
+	"ANSI":                       true,
+	"ANSI_QUOTES":                true,
+	"DB2":                        true,
+	"EMPTY_STRING_IS_NULL":       true,
+	"ERROR_FOR_DIVISION_BY_ZERO": true,
+	"HIGH_NOT_PRECEDENCE":        true,
+	"IGNORE_BAD_TABLE_OPTIONS":   true,
+	"IGNORE_SPACE":               true,
+	"MAXDB":                      true,
+	"MSSQL":                      true,
+	"MYSQL323":                   true,
+	"MYSQL40":                    true,
+	"NO_AUTO_CREATE_USER":        true,
+	"NO_AUTO_VALUE_ON_ZERO":      true,
+	"NO_BACKSLASH_ESCAPES":       true,
+	"NO_DIR_IN_CREATE":           true,
+	"NO_ENGINE_SUBSTITUTION":     true,
+	"NO_FIELD_OPTIONS":           true,
+	"NO_KEY_OPTIONS":             true,
+	"NO_TABLE_OPTIONS":           true,
+	"NO_UNSIGNED_SUBTRACTION":    true,
+	"NO_ZERO_DATE":               true,
+	"NO_ZERO_IN_DATE":            true,
+	"ONLY_FULL_GROUP_BY":         true,
+	"ORACLE":                     true,
+	"PAD_CHAR_TO_FULL_LENGTH":    true,
+	"PIPES_AS_CONCAT":            true,
+	"POSTGRESQL":                 true,
+	"REAL_AS_FLOAT":              true,
+	"SIMULTANEOUS_ASSIGNMENT":    true,
+	"STRICT_ALL_TABLES":          true,
+	"STRICT_TRANS_TABLES":        true,
+	"TIME_ROUND_FRACTIONAL":      true,
+	"TIME_TRUNCATE_FRACTIONAL":   true,
+	"TRADITIONAL":                true,
+}
+
+// quotingSQLModes change how the statements the provider builds are parsed:
+// ANSI_QUOTES, also implied by ANSI, turns double quotes into identifier
+// quotes and NO_BACKSLASH_ESCAPES breaks the escapes of quoteString.
+// Backtick quoted identifiers are understood in every mode.
+var quotingSQLModes = map[string]bool{
+	"ANSI":                 true,
+	"ANSI_QUOTES":          true,
+	"NO_BACKSLASH_ESCAPES": true,
+}
+
+// compatibleSQLMode returns the session sql_mode of the server without
+// quotingSQLModes, and whether any of them were set.
+func compatibleSQLMode(db *sql.DB) (string, bool, error) {
+	stmtSQL := "SELECT @@SESSION.sql_mode"
+	logQuery(stmtSQL)
+
+	var sqlMode string
+	if err := db.QueryRow(stmtSQL).Scan(&sqlMode); err != nil {
+		return "", false, err
+	}
+
+	var modes []string
+	changed := false
+	for _, mode := range strings.Split(sqlMode, ",") {
+		if quotingSQLModes[strings.ToUpper(mode)] {
+			changed = true
+			continue
+		}
+		if mode != "" {
+			modes = append(modes, mode)
+		}
+	}
+
+	return strings.Join(modes, ","), changed, nil
+}
+
+func validateSQLMode(v interface{}, k string) (ws []string, errors []error) {
+	for _, mode := range strings.Split(v.(string), ",") {
+		mode = strings.TrimSpace(mode)
+		if !knownSQLModes[strings.ToUpper(mode)] {
+			errors = append(errors, fmt.Errorf("%q contains an unknown SQL mode %q", k, mode))
+		}
+	}
+	return
+}
+
+// normalizeSQLMode upper-cases the modes and drops the blanks around them.
+func normalizeSQLMode(sqlMode string) string {
+	modes := strings.Split(sqlMode, ",")
+	for i, mode := range modes {
+		modes[i] = strings.ToUpper(strings.TrimSpace(mode))
+	}
+	return strings.Join(modes, ",")
+}
@@ -0,0 +1,98 @@
+package mysql_provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProviderConfigureQuotingSQLModes(t *testing.T) {
+	cases := []struct {
+		serverSQLMode string
+		sessionParam  string
+	}{
+		{"STRICT_TRANS_TABLES,NO_ENGINE_SUBSTITUTION", ""},
+		{"ANSI_QUOTES,STRICT_TRANS_TABLES", "'STRICT_TRANS_TABLES'"},
+		{"REAL_AS_FLOAT,PIPES_AS_CONCAT,ANSI_QUOTES,IGNORE_SPACE,ONLY_FULL_GROUP_BY,ANSI", "'REAL_AS_FLOAT,PIPES_AS_CONCAT,IGNORE_SPACE,ONLY_FULL_GROUP_BY'"},
+		{"NO_BACKSLASH_ESCAPES", "''"},
+	}
+	for _, c := range cases {
+		server := newFakeServer(t, func(query string) fakeResult {
+			if query == "SELECT @@SESSION.sql_mode" {
+				return fakeRow("@@SESSION.sql_mode", c.serverSQLMode)
+			}
+			return fakeDefaultResult(query)
+		})
+		conf, err := testProviderConfigure(t, map[string]interface{}{"endpoint": server.addr()})
+		if err != nil {
+			t.Fatalf("providerConfigure returned %s", err)
+		}
+		if got := conf.Config.Params["sql_mode"]; got != c.sessionParam {
+			t.Errorf("with the server sql_mode %q the session sql_mode is %q, want %q", c.serverSQLMode, got, c.sessionParam)
+		}
+		if c.sessionParam != "" && !fakeQueriesContain(server.receivedQueries(), "SET sql_mode="+c.sessionParam) {
+			t.Errorf("with the server sql_mode %q the session sql_mode wasn't set, ran %q", c.serverSQLMode, server.receivedQueries())
+		}
+	}
+}
+
+func TestProviderConfigureSQLMode(t *testing.T) {
+	server := newFakeServer(t, nil)
+	conf, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint": server.addr(),
+		"sql_mode": "strict_trans_tables, no_zero_date",
+	})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+
+	server.dropConnections()
+	before := len(server.receivedQueries())
+	if _, err := conf.connection().Exec("DO 1"); err != nil {
+		t.Fatalf("Exec returned %s", err)
+	}
+	queries := server.receivedQueries()[before:]
+	if !fakeQueriesContain(queries, "sql_mode='STRICT_TRANS_TABLES,NO_ZERO_DATE'") {
+		t.Errorf("the new connection ran %q, want the sql_mode set", queries)
+	}
+
+	_, err = testProviderConfigure(t, map[string]interface{}{
+		"endpoint":          server.addr(),
+		"sql_mode":          "STRICT_TRANS_TABLES",
+		"session_variables": map[string]interface{}{"sql_mode": "TRADITIONAL"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "sql_mode can't also be set") {
+		t.Errorf("providerConfigure with sql_mode set twice returned %v", err)
+	}
+}
+
+func TestValidateSQLMode(t *testing.T) {
+	valid := []string{
+		"STRICT_TRANS_TABLES",
+		"strict_trans_tables, NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO",
+		"ANSI_QUOTES",
+	}
+	for _, sqlMode := range valid {
+		if _, errs := validateSQLMode(sqlMode, "sql_mode"); len(errs) != 0 {
+			t.Errorf("validateSQLMode(%q) returned %v", sqlMode, errs)
+		}
+	}
+
+	invalid := []string{"STRICT_TABLES", "STRICT_TRANS_TABLES,,NO_ZERO_DATE", "STRICT_TRANS_TABLES;DROP"}
+	for _, sqlMode := range invalid {
+		if _, errs := validateSQLMode(sqlMode, "sql_mode"); len(errs) == 0 {
+			t.Errorf("validateSQLMode(%q) returned no errors", sqlMode)
+		}
+	}
+}
+
+func TestNormalizeSQLMode(t *testing.T) {
+	cases := map[string]string{
+		"strict_trans_tables":                "STRICT_TRANS_TABLES",
+		" no_zero_date , STRICT_ALL_TABLES ": "NO_ZERO_DATE,STRICT_ALL_TABLES",
+	}
+	for in, expected := range cases {
+		if got := normalizeSQLMode(in); got != expected {
+			t.Errorf("normalizeSQLMode(%q) = %q, want %q", in, got, expected)
+		}
+	}
+}
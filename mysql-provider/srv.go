@@ -0,0 +1,38 @@
+package mysql_provider
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// lookupSRV is swapped out where the resolver has to be faked.
+var lookupSRV = net.LookupSRV
+
+// srvEndpoints resolves a DNS SRV record, e.g. "_mysql._tcp.example.com",
+// into host:port endpoints ordered by lowest priority first, highest weight
+// first among equal priorities.
+func srvEndpoints(record string) ([]string, error) {
+	_, addrs, err := lookupSRV("", "", record)
+	if err != nil {
+		return nil, fmt.Errorf("Could not resolve srv_record %s: %s", record, err)
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("srv_record %s has no targets", record)
+	}
+
+	sort.SliceStable(addrs, func(i, j int) bool {
+		if addrs[i].Priority != addrs[j].Priority {
+			return addrs[i].Priority < addrs[j].Priority
+		}
+		return addrs[i].Weight > addrs[j].Weight
+	})
+
+	endpoints := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		endpoints = append(endpoints, net.JoinHostPort(strings.TrimSuffix(addr.Target, "."), strconv.Itoa(int(addr.Port))))
+	}
+	return endpoints, nil
+}
@@ -0,0 +1,77 @@
+package mysql_provider
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// testLookupSRV makes srvEndpoints resolve record to addrs until the end of
+// the test, any other record fails to resolve.
+func testLookupSRV(t *testing.T, record string, addrs []*net.SRV) {
+	previous := lookupSRV
+	lookupSRV = func(service, proto, name string) (string, []*net.SRV, error) {
+		if name != record {
+			return "", nil, errors.New("no such host")
+		}
+		return name, addrs, nil
+	}
+	t.Cleanup(func() { lookupSRV = previous })
+}
+
+func TestSRVEndpoints(t *testing.T) {
+	testLookupSRV(t, "_mysql._tcp.example.com", []*net.SRV{
+		{Target: "backup.example.com.", Port: 3306, Priority: 20, Weight: 100},
+		{Target: "small.example.com.", Port: 3306, Priority: 10, Weight: 10},
+		{Target: "big.example.com.", Port: 3307, Priority: 10, Weight: 90},
+	})
+
+	endpoints, err := srvEndpoints("_mysql._tcp.example.com")
+	if err != nil {
+		t.Fatalf("srvEndpoints returned %s", err)
+	}
+	expected := []string{"big.example.com:3307", "small.example.com:3306", "backup.example.com:3306"}
+	if !reflect.DeepEqual(endpoints, expected) {
+		t.Errorf("srvEndpoints = %q, want %q", endpoints, expected)
+	}
+
+	if _, err := srvEndpoints("_mysql._tcp.missing.example.com"); err == nil {
+		t.Error("srvEndpoints of an unresolvable record returned no error")
+	}
+}
+
+func TestSRVEndpointsWithoutTargets(t *testing.T) {
+	testLookupSRV(t, "_mysql._tcp.example.com", nil)
+	if _, err := srvEndpoints("_mysql._tcp.example.com"); err == nil {
+		t.Error("srvEndpoints of a record without targets returned no error")
+	}
+}
+
+func TestProviderConfigureSRVRecordFailover(t *testing.T) {
+	testSetenv(t, "MYSQL_ENDPOINT", "")
+	server := newFakeServer(t, nil)
+	_, port, _ := net.SplitHostPort(server.addr())
+	livePort, _ := strconv.Atoi(port)
+
+	// Nothing listens on a port that was just closed.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadPort := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	testLookupSRV(t, "_mysql._tcp.example.com", []*net.SRV{
+		{Target: "127.0.0.1.", Port: uint16(livePort), Priority: 20},
+		{Target: "127.0.0.1.", Port: uint16(deadPort), Priority: 10},
+	})
+	conf, err := testProviderConfigure(t, map[string]interface{}{"srv_record": "_mysql._tcp.example.com"})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+	if conf.Config.Addr != server.addr() {
+		t.Errorf("providerConfigure connected to %s, want the live target %s", conf.Config.Addr, server.addr())
+	}
+}
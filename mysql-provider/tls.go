@@ -0,0 +1,159 @@
+package mysql_provider
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+const pemPrefix = "-----BEGIN"
+
+// tlsVersions maps tls_min_version to the crypto/tls constants.
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// hasCustomTLS reports whether any certificate material or TLS restriction
+// was configured.
+func hasCustomTLS(d *schema.ResourceData) bool {
+	return d.Get("tls_ca_cert").(string) != "" ||
+		d.Get("tls_client_cert").(string) != "" ||
+		d.Get("tls_client_key").(string) != "" ||
+		d.Get("tls_min_version").(string) != "" ||
+		len(d.Get("tls_cipher_suites").([]interface{})) > 0
+}
+
+// validateTLSSettings rejects tls values that contradict the certificate
+// material. As tls defaults to false, setting any certificates implies TLS
+// rather than being an error.
+func validateTLSSettings(d *schema.ResourceData) error {
+	if !hasCustomTLS(d) {
+		return nil
+	}
+
+	switch d.Get("tls").(string) {
+	case "skip-verify":
+		if d.Get("tls_ca_cert").(string) != "" {
+			return fmt.Errorf("tls_ca_cert can't be used with tls = \"skip-verify\", which never checks the server certificate against it")
+		}
+	case "false":
+		log.Printf("[INFO] tls_* settings are set, connecting with TLS although tls is \"false\"")
+	}
+
+	return nil
+}
+
+// registerCustomTLS builds a tls.Config out of the tls_* provider attributes,
+// registers it with the driver and returns the name it was registered under.
+func registerCustomTLS(d *schema.ResourceData) (string, error) {
+	tlsConfig, err := customTLSConfig(d)
+	if err != nil {
+		return "", err
+	}
+
+	// Derive the name from the configuration so that re-configuring the
+	// provider with the same material reuses the same registration.
+	settings := []string{
+		d.Get("tls").(string),
+		d.Get("tls_ca_cert").(string),
+		d.Get("tls_client_cert").(string),
+		d.Get("tls_client_key").(string),
+		d.Get("tls_min_version").(string),
+	}
+	for _, suite := range d.Get("tls_cipher_suites").([]interface{}) {
+		settings = append(settings, suite.(string))
+	}
+	sum := sha256.Sum256([]byte(strings.Join(settings, "\x00")))
+	name := fmt.Sprintf("custom-%x", sum[:8])
+
+	err = mysql.RegisterTLSConfig(name, tlsConfig)
+	if err != nil {
+		return "", fmt.Errorf("Could not register TLS config: %s", err)
+	}
+
+	return name, nil
+}
+
+// customTLSConfig leaves ServerName empty, the driver fills it in from the
+// address of each endpoint it connects to.
+func customTLSConfig(d *schema.ResourceData) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: d.Get("tls").(string) == "skip-verify",
+	}
+
+	caCert, err := readPEM(d.Get("tls_ca_cert").(string))
+	if err != nil {
+		return nil, fmt.Errorf("Could not read tls_ca_cert: %s", err)
+	}
+	if len(caCert) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("tls_ca_cert does not contain any valid PEM certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	clientCert, err := readPEM(d.Get("tls_client_cert").(string))
+	if err != nil {
+		return nil, fmt.Errorf("Could not read tls_client_cert: %s", err)
+	}
+	clientKey, err := readPEM(d.Get("tls_client_key").(string))
+	if err != nil {
+		return nil, fmt.Errorf("Could not read tls_client_key: %s", err)
+	}
+	if len(clientCert) > 0 || len(clientKey) > 0 {
+		if len(clientCert) == 0 || len(clientKey) == 0 {
+			return nil, fmt.Errorf("tls_client_cert and tls_client_key must be set together")
+		}
+		certificate, err := tls.X509KeyPair(clientCert, clientKey)
+		if err != nil {
+			return nil, fmt.Errorf("Could not load client certificate: %s", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	if version := d.Get("tls_min_version").(string); version != "" {
+		tlsConfig.MinVersion = tlsVersions[version]
+	}
+
+	for _, name := range d.Get("tls_cipher_suites").([]interface{}) {
+		id, ok := cipherSuiteID(name.(string))
+		if !ok {
+			return nil, fmt.Errorf("tls_cipher_suites contains an unknown or insecure cipher suite %q", name)
+		}
+		tlsConfig.CipherSuites = append(tlsConfig.CipherSuites, id)
+	}
+
+	return tlsConfig, nil
+}
+
+// cipherSuiteID looks up a cipher suite among the ones crypto/tls considers
+// secure.
+func cipherSuiteID(name string) (uint16, bool) {
+	for _, suite := range tls.CipherSuites() {
+		if suite.Name == name {
+			return suite.ID, true
+		}
+	}
+	return 0, false
+}
+
+// readPEM accepts either inline PEM contents or a path to a PEM file, which
+// allows pointing straight at bundles such as the RDS/Aurora CA bundle.
+func readPEM(value string) ([]byte, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if strings.HasPrefix(strings.TrimSpace(value), pemPrefix) {
+		return []byte(value), nil
+	}
+	return ioutil.ReadFile(value)
+}
@@ -0,0 +1,186 @@
+package mysql_provider
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// testCertificatePEM returns a self-signed certificate and its key, PEM
+// encoded.
+func testCertificatePEM(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "mysql"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestCustomTLSConfig(t *testing.T) {
+	cert, key := testCertificatePEM(t)
+	d := schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, map[string]interface{}{
+		"tls":             "true",
+		"tls_ca_cert":     cert,
+		"tls_client_cert": cert,
+		"tls_client_key":  key,
+	})
+	tlsConfig, err := customTLSConfig(d)
+	if err != nil {
+		t.Fatalf("customTLSConfig returned %s", err)
+	}
+	if tlsConfig.InsecureSkipVerify {
+		t.Error("InsecureSkipVerify is set for tls = \"true\"")
+	}
+	if tlsConfig.RootCAs == nil {
+		t.Error("RootCAs is not set from tls_ca_cert")
+	}
+	if len(tlsConfig.Certificates) != 1 {
+		t.Errorf("customTLSConfig loaded %d client certificates, want 1", len(tlsConfig.Certificates))
+	}
+
+	d = schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, map[string]interface{}{
+		"tls": "skip-verify",
+	})
+	tlsConfig, err = customTLSConfig(d)
+	if err != nil {
+		t.Fatalf("customTLSConfig returned %s", err)
+	}
+	if !tlsConfig.InsecureSkipVerify {
+		t.Error("InsecureSkipVerify is not set for tls = \"skip-verify\"")
+	}
+}
+
+func TestCustomTLSConfigErrors(t *testing.T) {
+	cert, _ := testCertificatePEM(t)
+	cases := map[string]map[string]interface{}{
+		"invalid ca cert":      {"tls_ca_cert": "-----BEGIN CERTIFICATE-----\nnot a certificate\n-----END CERTIFICATE-----"},
+		"missing ca cert file": {"tls_ca_cert": filepath.Join(os.TempDir(), "tf-mysql-does-not-exist.pem")},
+		"client cert alone":    {"tls_client_cert": cert},
+	}
+	for name, raw := range cases {
+		d := schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw)
+		if _, err := customTLSConfig(d); err == nil {
+			t.Errorf("customTLSConfig with %s returned no error", name)
+		}
+	}
+}
+
+func TestCustomTLSConfigRestrictions(t *testing.T) {
+	for version, expected := range map[string]uint16{"1.2": tls.VersionTLS12, "1.3": tls.VersionTLS13} {
+		d := schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, map[string]interface{}{
+			"tls":             "true",
+			"tls_min_version": version,
+		})
+		tlsConfig, err := customTLSConfig(d)
+		if err != nil {
+			t.Fatalf("customTLSConfig returned %s", err)
+		}
+		if tlsConfig.MinVersion != expected {
+			t.Errorf("tls_min_version = %q set MinVersion %#x, want %#x", version, tlsConfig.MinVersion, expected)
+		}
+	}
+
+	validate := Provider().(*schema.Provider).Schema["tls_min_version"].ValidateFunc
+	for _, version := range []string{"1.0", "1.1", "TLS1.2"} {
+		if _, errs := validate(version, "tls_min_version"); len(errs) == 0 {
+			t.Errorf("tls_min_version = %q passed validation", version)
+		}
+	}
+
+	d := schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, map[string]interface{}{
+		"tls":               "true",
+		"tls_cipher_suites": []interface{}{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384"},
+	})
+	tlsConfig, err := customTLSConfig(d)
+	if err != nil {
+		t.Fatalf("customTLSConfig returned %s", err)
+	}
+	expected := []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384}
+	if len(tlsConfig.CipherSuites) != len(expected) || tlsConfig.CipherSuites[0] != expected[0] || tlsConfig.CipherSuites[1] != expected[1] {
+		t.Errorf("tls_cipher_suites set CipherSuites %#x, want %#x", tlsConfig.CipherSuites, expected)
+	}
+
+	for _, suite := range []string{"TLS_RSA_WITH_RC4_128_SHA", "TLS_NO_SUCH_SUITE"} {
+		d := schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, map[string]interface{}{
+			"tls":               "true",
+			"tls_cipher_suites": []interface{}{suite},
+		})
+		if _, err := customTLSConfig(d); err == nil {
+			t.Errorf("customTLSConfig accepted the cipher suite %s", suite)
+		}
+	}
+}
+
+func TestReadPEM(t *testing.T) {
+	cert, _ := testCertificatePEM(t)
+	if got, err := readPEM(cert); err != nil || string(got) != cert {
+		t.Errorf("readPEM(inline) = %q, %v", got, err)
+	}
+
+	file, err := ioutil.TempFile("", "tf-mysql-*.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.WriteString(cert); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+	if got, err := readPEM(file.Name()); err != nil || string(got) != cert {
+		t.Errorf("readPEM(%q) = %q, %v", file.Name(), got, err)
+	}
+
+	if got, err := readPEM(""); err != nil || got != nil {
+		t.Errorf("readPEM(\"\") = %q, %v, want nothing", got, err)
+	}
+}
+
+func TestValidateTLSSettings(t *testing.T) {
+	cert, key := testCertificatePEM(t)
+	cases := []struct {
+		raw   map[string]interface{}
+		valid bool
+	}{
+		{map[string]interface{}{"tls": "skip-verify", "tls_ca_cert": cert}, false},
+		{map[string]interface{}{"tls": "skip-verify", "tls_client_cert": cert, "tls_client_key": key}, true},
+		// Certificate material implies TLS.
+		{map[string]interface{}{"tls": "false", "tls_ca_cert": cert}, true},
+		{map[string]interface{}{"tls": "true", "tls_ca_cert": cert}, true},
+		{map[string]interface{}{"tls": "skip-verify"}, true},
+	}
+	for _, c := range cases {
+		d := schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, c.raw)
+		if err := validateTLSSettings(d); (err == nil) != c.valid {
+			t.Errorf("validateTLSSettings(tls = %q) = %v, want valid %t", c.raw["tls"], err, c.valid)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package mysql_provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const vaultRequestTimeout = 30 * time.Second
+
+// vaultCredentialsResponse is the part of a database secrets engine creds
+// response the provider needs.
+type vaultCredentialsResponse struct {
+	LeaseDuration int `json:"lease_duration"`
+	Data          struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	} `json:"data"`
+	Errors []string `json:"errors"`
+}
+
+// vaultCredentialsFunc returns a function handing out dynamic credentials from
+// the database secrets engine mounted at mount. Credentials are reused until
+// most of their lease has run out, then new ones are requested so connections
+// made later on, e.g. when reconnecting, don't use revoked credentials.
+func vaultCredentialsFunc(addr string, token string, mount string, role string) func() (string, string, error) {
+	client := &http.Client{Timeout: vaultRequestTimeout}
+	url := fmt.Sprintf("%s/v1/%s/creds/%s", strings.TrimRight(addr, "/"), strings.Trim(mount, "/"), role)
+
+	var lock sync.Mutex
+	var username, password string
+	var expires time.Time
+
+	return func() (string, string, error) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if username != "" && time.Now().Before(expires) {
+			return username, password, nil
+		}
+
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			return "", "", fmt.Errorf("Could not request Vault credentials: %s", err)
+		}
+		req.Header.Set("X-Vault-Token", token)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", "", fmt.Errorf("Could not request Vault credentials: %s", err)
+		}
+		defer resp.Body.Close()
+
+		var creds vaultCredentialsResponse
+		if err := json.NewDecoder(resp.Body).Decode(&creds); err != nil {
+			return "", "", fmt.Errorf("Could not decode Vault credentials: %s", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return "", "", fmt.Errorf("Could not request Vault credentials: %s: %s", resp.Status, strings.Join(creds.Errors, ", "))
+		}
+
+		username = creds.Data.Username
+		password = creds.Data.Password
+		expires = time.Now().Add(time.Duration(creds.LeaseDuration) * time.Second * 9 / 10)
+		return username, password, nil
+	}
+}
@@ -0,0 +1,104 @@
+package mysql_provider
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testVault is a Vault server mocking the database secrets engine mounted at
+// database, handing out new credentials for role app on every request.
+type testVault struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests int
+}
+
+func newTestVault(t *testing.T, leaseDuration int) *testVault {
+	v := &testVault{}
+	v.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"errors":["permission denied"]}`)
+			return
+		}
+		if r.Method != "GET" || r.URL.Path != "/v1/database/creds/app" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, `{"errors":["no handler for route %s"]}`, r.URL.Path)
+			return
+		}
+		v.mu.Lock()
+		v.requests++
+		n := v.requests
+		v.mu.Unlock()
+		fmt.Fprintf(w, `{"lease_duration":%d,"data":{"username":"v-app-%d","password":"pw-%d"}}`, leaseDuration, n, n)
+	}))
+	t.Cleanup(v.Close)
+	return v
+}
+
+func (v *testVault) credentialRequests() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.requests
+}
+
+func TestVaultCredentialsFunc(t *testing.T) {
+	vault := newTestVault(t, 3600)
+	credentials := vaultCredentialsFunc(vault.URL+"/", "root", "/database/", "app")
+	for i := 0; i < 2; i++ {
+		username, password, err := credentials()
+		if err != nil {
+			t.Fatalf("credentials returned %s", err)
+		}
+		if username != "v-app-1" || password != "pw-1" {
+			t.Errorf("credentials = %q, %q, want v-app-1, pw-1", username, password)
+		}
+	}
+	if n := vault.credentialRequests(); n != 1 {
+		t.Errorf("credentials requested %d times within their lease, want 1", n)
+	}
+
+	expired := newTestVault(t, 0)
+	credentials = vaultCredentialsFunc(expired.URL, "root", "database", "app")
+	credentials()
+	if username, _, _ := credentials(); username != "v-app-2" {
+		t.Errorf("credentials past their lease = %q, want the new v-app-2", username)
+	}
+
+	_, _, err := vaultCredentialsFunc(vault.URL, "wrong", "database", "app")()
+	if err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("credentials with a wrong token returned %v, want permission denied", err)
+	}
+}
+
+func TestProviderConfigureVault(t *testing.T) {
+	testSetenv(t, "MYSQL_PASSWORD", "")
+	vault := newTestVault(t, 0)
+	server := newFakeServer(t, nil)
+
+	conf, err := testProviderConfigure(t, map[string]interface{}{
+		"endpoint":    server.addr(),
+		"username":    "",
+		"password":    "",
+		"vault_addr":  vault.URL,
+		"vault_token": "root",
+		"vault_role":  "app",
+	})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+	if conf.Config.User != "v-app-1" || conf.Config.Passwd != "pw-1" {
+		t.Errorf("providerConfigure connected as %q, %q, want the Vault credentials v-app-1, pw-1", conf.Config.User, conf.Config.Passwd)
+	}
+
+	// Reconnecting asks for new credentials once the lease ran out.
+	server.dropConnections()
+	conf.connection()
+	if conf.Config.User != "v-app-2" {
+		t.Errorf("reconnected as %q, want the fresh Vault credentials v-app-2", conf.Config.User)
+	}
+}
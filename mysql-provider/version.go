@@ -2,17 +2,66 @@ package mysql_provider
 
 import (
 	"database/sql"
+	"fmt"
+	"github.com/go-sql-driver/mysql"
 	"github.com/hashicorp/go-version"
+	"strings"
 )
 
-func mySQLServerVersion(db *sql.DB) (*version.Version, error) {
-	var versionString string
-	err := db.QueryRow("SELECT @@GLOBAL.innodb_version").Scan(&versionString)
+const unknownFunctionErr = 1305
+
+// mariaDBReplicationPrefix is prepended to the version by MariaDB servers to
+// stay compatible with MySQL 5.x replication clients.
+const mariaDBReplicationPrefix = "5.5.5-"
+
+// serverVersionString returns the server's @@GLOBAL.version, which is cached
+// per provider configuration. The lock isn't held while querying, so a slow
+// server doesn't hold up the other lookups.
+func (c *MySQLConfiguration) serverVersionString() (string, error) {
+	c.cacheLock.Lock()
+	versionString := c.versionString
+	c.cacheLock.Unlock()
+	if versionString != "" {
+		return versionString, nil
+	}
+
+	versionString, err := mySQLServerVersionString(c.connection())
+	if err != nil {
+		return "", err
+	}
+
+	c.cacheLock.Lock()
+	defer c.cacheLock.Unlock()
+	c.versionString = versionString
+
+	return versionString, nil
+}
+
+// serverVersion is the parsed form of serverVersionString.
+func (c *MySQLConfiguration) serverVersion() (*version.Version, error) {
+	versionString, err := c.serverVersionString()
 	if err != nil {
 		return nil, err
 	}
 
-	return version.NewVersion(versionString)
+	return parseServerVersion(versionString)
+}
+
+// parseServerVersion extracts the numeric version out of a server banner such
+// as "8.0.34-0ubuntu0.22.04.1" or "5.5.5-10.6.12-MariaDB-log".
+func parseServerVersion(versionString string) (*version.Version, error) {
+	if strings.Contains(versionString, "MariaDB") {
+		versionString = strings.TrimPrefix(versionString, mariaDBReplicationPrefix)
+	}
+	if i := strings.IndexRune(versionString, '-'); i != -1 {
+		versionString = versionString[:i]
+	}
+
+	parsed, err := version.NewVersion(versionString)
+	if err != nil {
+		return nil, fmt.Errorf("Could not parse server version %q: %s", versionString, err)
+	}
+	return parsed, nil
 }
 
 func mySQLServerVersionString(db *sql.DB) (string, error) {
@@ -24,3 +73,97 @@ func mySQLServerVersionString(db *sql.DB) (string, error) {
 
 	return versionString, nil
 }
+
+// requireMySQL8 returns an error describing the feature when the server is
+// not MySQL 8.0 or later. MariaDB is rejected as well since it implements
+// roles and related account features differently.
+func requireMySQL8(conf *MySQLConfiguration, feature string) error {
+	return requireMySQLVersion(conf, feature, "8.0.0")
+}
+
+// requireMySQLVersion is the generalised form of requireMySQL8 for features
+// introduced in a later MySQL release.
+func requireMySQLVersion(conf *MySQLConfiguration, feature string, minVersion string) error {
+	versionString, err := conf.serverVersionString()
+	if err != nil {
+		return err
+	}
+	if strings.Contains(versionString, "MariaDB") {
+		return fmt.Errorf("%s requires MySQL %s or newer, MariaDB %s is not supported", feature, minVersion, versionString)
+	}
+
+	requiredVersion, _ := version.NewVersion(minVersion)
+	currentVersion, err := parseServerVersion(versionString)
+	if err != nil {
+		return err
+	}
+	if currentVersion.LessThan(requiredVersion) {
+		return fmt.Errorf("%s requires MySQL %s or newer, server version is %s", feature, minVersion, currentVersion)
+	}
+
+	return nil
+}
+
+// requireMariaDBVersion is the MariaDB counterpart of requireMySQLVersion,
+// for features MySQL doesn't have.
+func requireMariaDBVersion(conf *MySQLConfiguration, feature string, minVersion string) error {
+	ok, err := isMariaDBVersion(conf, minVersion)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%s requires MariaDB %s or newer", feature, minVersion)
+	}
+
+	return nil
+}
+
+// isMariaDBVersion reports whether the server is MariaDB minVersion or newer.
+func isMariaDBVersion(conf *MySQLConfiguration, minVersion string) (bool, error) {
+	versionString, err := conf.serverVersionString()
+	if err != nil {
+		return false, err
+	}
+	if !strings.Contains(versionString, "MariaDB") {
+		return false, nil
+	}
+
+	requiredVersion, _ := version.NewVersion(minVersion)
+	currentVersion, err := parseServerVersion(versionString)
+	if err != nil {
+		return false, err
+	}
+	return !currentVersion.LessThan(requiredVersion), nil
+}
+
+// rdsBaseDirPrefix is where RDS, Aurora included, installs the server.
+const rdsBaseDirPrefix = "/rdsdbbin/"
+
+// isRDS reports whether the server is managed by Amazon RDS, Aurora included.
+func isRDS(db *sql.DB) (bool, error) {
+	stmtSQL := "SELECT @@GLOBAL.basedir"
+	logQuery(stmtSQL)
+
+	var baseDir string
+	if err := db.QueryRow(stmtSQL).Scan(&baseDir); err != nil {
+		return false, err
+	}
+	return strings.HasPrefix(baseDir, rdsBaseDirPrefix), nil
+}
+
+// isAurora reports whether the server is Aurora MySQL, the only flavour to
+// have the AURORA_VERSION() function.
+func isAurora(db *sql.DB) (bool, error) {
+	stmtSQL := "SELECT AURORA_VERSION()"
+	logQuery(stmtSQL)
+
+	var auroraVersion string
+	err := db.QueryRow(stmtSQL).Scan(&auroraVersion)
+	if err != nil {
+		if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == unknownFunctionErr {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,70 @@
+package mysql_provider
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestParseServerVersion(t *testing.T) {
+	cases := map[string]string{
+		"8.0.34":                          "8.0.34",
+		"8.0.34-0ubuntu0.22.04.1":         "8.0.34",
+		"5.7.44-log":                      "5.7.44",
+		"5.5.5-10.6.12-MariaDB-log":       "10.6.12",
+		"10.11.2-MariaDB":                 "10.11.2",
+		"5.5.5-10.3.39-MariaDB-0+deb10u1": "10.3.39",
+	}
+	for in, expected := range cases {
+		parsed, err := parseServerVersion(in)
+		if err != nil {
+			t.Errorf("parseServerVersion(%q) returned %s", in, err)
+			continue
+		}
+		if parsed.String() != expected {
+			t.Errorf("parseServerVersion(%q) = %s, want %s", in, parsed, expected)
+		}
+	}
+
+	if _, err := parseServerVersion("unknown"); err == nil {
+		t.Error("parseServerVersion(\"unknown\") returned no error")
+	}
+}
+
+func TestServerVersionStringCached(t *testing.T) {
+	server := newFakeServer(t, nil)
+	conf, err := testProviderConfigure(t, map[string]interface{}{"endpoint": server.addr()})
+	if err != nil {
+		t.Fatalf("providerConfigure returned %s", err)
+	}
+
+	before := len(server.receivedQueries())
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if versionString, err := conf.serverVersionString(); err != nil || versionString != fakeServerVersion {
+				t.Errorf("serverVersionString = %q, %v, want %q", versionString, err, fakeServerVersion)
+			}
+		}()
+	}
+	wg.Wait()
+	conf.serverVersionString()
+
+	queried := 0
+	for _, query := range server.receivedQueries()[before:] {
+		if query == "SELECT @@GLOBAL.version" {
+			queried++
+		}
+	}
+	// Concurrent first lookups may each query the server, later ones don't.
+	if queried == 0 || queried > 4 {
+		t.Errorf("the version was queried %d times, want once per concurrent first lookup", queried)
+	}
+
+	before = len(server.receivedQueries())
+	conf.serverVersionString()
+	if fakeQueriesContain(server.receivedQueries()[before:], "SELECT @@GLOBAL.version") {
+		t.Error("serverVersionString queried the cached version again")
+	}
+}